@@ -0,0 +1,94 @@
+package schwab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// AccountType is the normalized kind of a Schwab account.
+type AccountType string
+
+const (
+	AccountTypeBrokerage AccountType = "Brokerage"
+	AccountTypeIRA       AccountType = "IRA"
+	AccountTypeRothIRA   AccountType = "RothIRA"
+	AccountTypeOther     AccountType = "Other"
+)
+
+// ListAccounts returns the accounts available to the logged-in user via AccountInfoV2Url.
+func (c *Client) ListAccounts() ([]Account, error) {
+	return c.ListAccountsContext(context.Background())
+}
+
+// ListAccountsContext is like ListAccounts but uses ctx for cancellation and deadlines.
+func (c *Client) ListAccountsContext(ctx context.Context) ([]Account, error) {
+	if err := c.UpdateTokenContext(ctx, "api"); err != nil && c.Debug {
+		log.Printf("UpdateToken(api) warning: %v", err)
+	}
+
+	u := c.endpoint(AccountInfoV2Url)
+	status, body, err := c.do(ctx, "GET", u, nil, map[string]string{
+		"schwab-resource-version": "1.0",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status != 200 {
+		return nil, newAPIError("GET", u, status, body)
+	}
+
+	var data AccountsV2Response
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	accounts := make([]Account, 0, len(data.Accounts))
+	for _, a := range data.Accounts {
+		accounts = append(accounts, Account{
+			Number:   a.AccountId,
+			Nickname: a.NickName,
+			Type:     classifyAccountType(string(a.AccountType)),
+			RawType:  string(a.AccountType),
+			Color:    a.AccountColor,
+			Permissions: TradingPermissions{
+				Trading:      a.IsTradingAllowed,
+				Margin:       a.IsMarginEnabled,
+				OptionsLevel: a.OptionsTradingLevel,
+			},
+		})
+	}
+	return accounts, nil
+}
+
+// discoverAccounts fills AccountIDs from ListAccounts.
+func (c *Client) discoverAccounts(ctx context.Context) error {
+	accounts, err := c.ListAccountsContext(ctx)
+	if err != nil {
+		return fmt.Errorf("discover accounts: %w", err)
+	}
+	ids := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		ids = append(ids, a.Number)
+	}
+	c.setAccountIDs(ids)
+	if c.Debug {
+		log.Printf("Discovered %d account(s)", len(ids))
+	}
+	return nil
+}
+
+// classifyAccountType maps Schwab's account type text to an AccountType.
+func classifyAccountType(raw string) AccountType {
+	t := strings.ToLower(raw)
+	switch {
+	case strings.Contains(t, "roth"):
+		return AccountTypeRothIRA
+	case strings.Contains(t, "ira"):
+		return AccountTypeIRA
+	case strings.Contains(t, "brokerage"), strings.Contains(t, "individual"), strings.Contains(t, "joint"):
+		return AccountTypeBrokerage
+	}
+	return AccountTypeOther
+}
@@ -0,0 +1,43 @@
+package schwab
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestListAccounts(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/is.TradeOrderManagementWeb/v1/TradeOrderManagementWebPort/customer/accounts" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"Accounts":[
+			{"AccountId":"11112222","NickName":"Trading","AccountType":"Individual Brokerage","AccountColor":1,"IsTradingAllowed":true,"IsMarginEnabled":true,"OptionsTradingLevel":2},
+			{"AccountId":"33334444","NickName":"Retirement","AccountType":"Roth Contributory IRA","IsTradingAllowed":true},
+			{"AccountId":"55556666","AccountType":"Traditional IRA"}
+		]}`))
+	})
+
+	accounts, err := c.ListAccounts()
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	if len(accounts) != 3 {
+		t.Fatalf("got %d accounts, want 3", len(accounts))
+	}
+	a := accounts[0]
+	if a.Number != "11112222" || a.Nickname != "Trading" || a.Type != AccountTypeBrokerage || a.Color != 1 ||
+		!a.Permissions.Trading || !a.Permissions.Margin || a.Permissions.OptionsLevel != 2 {
+		t.Errorf("unexpected account: %+v", a)
+	}
+	if accounts[1].Type != AccountTypeRothIRA || accounts[2].Type != AccountTypeIRA {
+		t.Errorf("types = %s, %s", accounts[1].Type, accounts[2].Type)
+	}
+
+	if err := c.discoverAccounts(context.Background()); err != nil {
+		t.Fatalf("discoverAccounts: %v", err)
+	}
+	if len(c.AccountIDs) != 3 || c.AccountIDs[2] != "55556666" {
+		t.Errorf("AccountIDs = %v", c.AccountIDs)
+	}
+}
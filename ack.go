@@ -0,0 +1,101 @@
+package schwab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MessageClass is how an order message affects the order.
+type MessageClass string
+
+const (
+	// MessageInfo is informational only.
+	MessageInfo MessageClass = "info"
+	// MessageWarning must be acknowledged before the order can be executed
+	// (e.g. a large order or a hard-to-borrow stock).
+	MessageWarning MessageClass = "warning"
+	// MessageError rejects the order.
+	MessageError MessageClass = "error"
+)
+
+// ErrWarningsNotAcknowledged is returned when verification produced warnings that the client's AckPolicy did not acknowledge.
+var ErrWarningsNotAcknowledged = errors.New("schwab: order warnings not acknowledged")
+
+// AckPolicy decides which verification warnings to acknowledge before executing an order.
+// Warnings matching AutoAck are acknowledged; the rest are passed to Confirm. Without Confirm,
+// or if it returns false, the order is not executed. An empty AckPolicy rejects every warning.
+type AckPolicy struct {
+	// AutoAck lists warnings to acknowledge automatically, matched case-insensitively as substrings of the message.
+	AutoAck []string
+	// Confirm is asked about the remaining warnings; returning true acknowledges them.
+	Confirm func(ctx context.Context, warnings []TradeMessage) (bool, error)
+}
+
+// acknowledge reports whether all warnings are acknowledged under the policy.
+func (p *AckPolicy) acknowledge(ctx context.Context, warnings []TradeMessage) (bool, error) {
+	var pending []TradeMessage
+	for _, w := range warnings {
+		if !p.autoAcks(w.Message) {
+			pending = append(pending, w)
+		}
+	}
+	if len(pending) == 0 {
+		return true, nil
+	}
+	if p.Confirm == nil {
+		return false, nil
+	}
+	ok, err := p.Confirm(ctx, pending)
+	if err != nil {
+		return false, fmt.Errorf("confirm order warnings: %w", err)
+	}
+	return ok, nil
+}
+
+func (p *AckPolicy) autoAcks(message string) bool {
+	message = strings.ToLower(message)
+	for _, s := range p.AutoAck {
+		if s != "" && strings.Contains(message, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyOrderMessage classifies a message by its severity, which Schwab sends either as a
+// name ("Info", "Warning", "Error") or as a number on the same scale as OrderReturnCode
+// (0 info, 10 warning, 20 and above error). Messages without a usable severity are info;
+// the response's return code still decides whether the order can go ahead.
+func classifyOrderMessage(severity string) MessageClass {
+	s := strings.ToLower(strings.TrimSpace(severity))
+	switch {
+	case strings.Contains(s, "err"), strings.Contains(s, "fatal"), strings.Contains(s, "reject"):
+		return MessageError
+	case strings.Contains(s, "warn"), strings.Contains(s, "acknowledg"), strings.Contains(s, "confirm"):
+		return MessageWarning
+	}
+	n, err := strconv.Atoi(s)
+	switch {
+	case err != nil:
+		return MessageInfo
+	case n >= 20:
+		return MessageError
+	case n >= 10:
+		return MessageWarning
+	}
+	return MessageInfo
+}
+
+// messagesOfClass returns the messages with the given class.
+func messagesOfClass(messages []TradeMessage, class MessageClass) []TradeMessage {
+	var out []TradeMessage
+	for _, m := range messages {
+		if m.Class == class {
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,179 @@
+package schwab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClassifyOrderMessage(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     MessageClass
+	}{
+		{"Info", MessageInfo},
+		{"WARNING", MessageWarning},
+		{"Requires acknowledgement", MessageWarning},
+		{"Error", MessageError},
+		{"0", MessageInfo},
+		{"10", MessageWarning},
+		{"30", MessageError},
+		{"", MessageInfo},
+	}
+	for _, tt := range tests {
+		if got := classifyOrderMessage(tt.severity); got != tt.want {
+			t.Errorf("classifyOrderMessage(%q) = %s, want %s", tt.severity, got, tt.want)
+		}
+	}
+}
+
+// ackTestClient answers verification with a hard-to-borrow warning (return code 10)
+// and records whether execution was requested with the affirm flag.
+func ackTestClient(t *testing.T, affirmed *bool, executed *bool) *Client {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["OrderProcessingControl"] == 2.0 {
+			*executed = true
+			*affirmed = body["OrderStrategy"].(map[string]interface{})["OrderAffrmIn"] == true
+			w.Write([]byte(`{"orderStrategy":{"orderId":9,"orderReturnCode":0}}`))
+			return
+		}
+		w.Write([]byte(`{"orderStrategy":{"orderId":9,"orderReturnCode":10,"orderMessages":[
+			{"message":"This security is hard to borrow","severity":"Warning"},
+			{"message":"Market is closed; order will be queued","severity":"Info"}]}}`))
+	})
+}
+
+func TestAckPolicy(t *testing.T) {
+	order := OrderRequest{AccountID: "123", Symbol: "GME", Side: "Sell", Quantity: 1}
+
+	t.Run("no policy", func(t *testing.T) {
+		var affirmed, executed bool
+		c := ackTestClient(t, &affirmed, &executed)
+		result, err := c.PlaceOrder(order)
+		if err != nil || !executed || affirmed || result.Acknowledged {
+			t.Errorf("result = %+v, err = %v, affirmed = %v", result, err, affirmed)
+		}
+	})
+
+	t.Run("auto ack", func(t *testing.T) {
+		var affirmed, executed bool
+		c := ackTestClient(t, &affirmed, &executed)
+		c.AckPolicy = &AckPolicy{AutoAck: []string{"HARD TO BORROW"}}
+		result, err := c.PlaceOrder(order)
+		if err != nil || !result.Placed || !result.Acknowledged || !affirmed {
+			t.Errorf("result = %+v, err = %v, affirmed = %v", result, err, affirmed)
+		}
+	})
+
+	t.Run("callback", func(t *testing.T) {
+		var affirmed, executed bool
+		c := ackTestClient(t, &affirmed, &executed)
+		var asked []TradeMessage
+		c.AckPolicy = &AckPolicy{Confirm: func(ctx context.Context, warnings []TradeMessage) (bool, error) {
+			asked = warnings
+			return true, nil
+		}}
+		if _, err := c.PlaceOrder(order); err != nil || !affirmed {
+			t.Fatalf("err = %v, affirmed = %v", err, affirmed)
+		}
+		if len(asked) != 1 || asked[0].Class != MessageWarning {
+			t.Errorf("Confirm got %+v, want the one warning", asked)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		var affirmed, executed bool
+		c := ackTestClient(t, &affirmed, &executed)
+		c.AckPolicy = &AckPolicy{}
+		result, err := c.PlaceOrder(order)
+		if !errors.Is(err, ErrWarningsNotAcknowledged) || executed || result.Placed || !result.Verified {
+			t.Errorf("result = %+v, err = %v, executed = %v", result, err, executed)
+		}
+	})
+
+	t.Run("dry run skips policy", func(t *testing.T) {
+		var affirmed, executed bool
+		c := ackTestClient(t, &affirmed, &executed)
+		c.AckPolicy = &AckPolicy{Confirm: func(context.Context, []TradeMessage) (bool, error) {
+			t.Error("Confirm called for a dry run")
+			return false, nil
+		}}
+		dry := order
+		dry.DryRun = true
+		if result, err := c.PlaceOrder(dry); err != nil || !result.Verified || executed {
+			t.Errorf("result = %+v, err = %v", result, err)
+		}
+	})
+}
+
+func TestAckPolicy_ErrorMessageRejects(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orderStrategy":{"orderReturnCode":10,"orderMessages":[
+			{"message":"Large order","severity":"Warning"},
+			{"message":"Insufficient shares","severity":"Error"}]}}`))
+	})
+	c.AckPolicy = &AckPolicy{AutoAck: []string{"large order"}}
+	_, err := c.PlaceOrder(OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Sell", Quantity: 1})
+	if !errors.Is(err, ErrOrderRejected) {
+		t.Errorf("err = %v, want ErrOrderRejected", err)
+	}
+}
+
+func TestAckPolicy_UnknownSeverityIsInfo(t *testing.T) {
+	var affirmed, executed bool
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["OrderProcessingControl"] == 2.0 {
+			executed = true
+			affirmed = body["OrderStrategy"].(map[string]interface{})["OrderAffrmIn"] == true
+			w.Write([]byte(`{"orderStrategy":{"orderId":9,"orderReturnCode":0}}`))
+			return
+		}
+		w.Write([]byte(`{"orderStrategy":{"orderId":9,"orderReturnCode":10,"orderMessages":[
+			{"message":"Your order will be placed for the next trading session"}]}}`))
+	})
+	c.AckPolicy = &AckPolicy{Confirm: func(context.Context, []TradeMessage) (bool, error) {
+		t.Error("Confirm called for a message without severity")
+		return false, nil
+	}}
+	result, err := c.PlaceOrder(OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Buy", Quantity: 1})
+	if err != nil || !executed || affirmed || result.VerifyMessages[0].Class != MessageInfo {
+		t.Errorf("result = %+v, err = %v, executed = %v, affirmed = %v", result, err, executed, affirmed)
+	}
+}
+
+func TestAckPolicy_ErrorReturnCodeNeverExecutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{"warning with return code 25", `{"orderStrategy":{"orderId":9,"orderReturnCode":25,"orderMessages":[
+			{"message":"This security is hard to borrow","severity":"Warning"}]}}`},
+		{"error message with return code 0", `{"orderStrategy":{"orderId":9,"orderReturnCode":0,"orderMessages":[
+			{"message":"This security is hard to borrow","severity":"Warning"},
+			{"message":"Insufficient shares","severity":"Error"}]}}`},
+	}
+	for _, tt := range tests {
+		for _, policy := range []*AckPolicy{nil, {AutoAck: []string{"hard to borrow"}}} {
+			executed := false
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&body)
+				if body["OrderProcessingControl"] == 2.0 {
+					executed = true
+				}
+				w.Write([]byte(tt.response))
+			})
+			c.AckPolicy = policy
+			_, err := c.PlaceOrder(OrderRequest{AccountID: "123", Symbol: "GME", Side: "Sell", Quantity: 1})
+			if !errors.Is(err, ErrOrderRejected) || executed {
+				t.Errorf("%s (policy %v): err = %v, executed = %v", tt.name, policy != nil, err, executed)
+			}
+		}
+	}
+}
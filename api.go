@@ -2,53 +2,79 @@ package schwab
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 )
 
-// GetAccountInfo retrieves the account positions and balances.
+// GetAccountInfo retrieves the account positions and balances for every account in
+// AccountIDs (or the captured schwab-client-account when AccountIDs is empty).
+// If some accounts fail, the ones that succeeded are still returned together with an *AccountsError.
 func (c *Client) GetAccountInfo() (map[int64]AccountV2, error) {
-	if err := c.UpdateToken("api"); err != nil && c.Debug {
+	return c.GetAccountInfoContext(context.Background())
+}
+
+// GetAccountInfoContext is like GetAccountInfo but uses ctx for cancellation and deadlines.
+func (c *Client) GetAccountInfoContext(ctx context.Context) (map[int64]AccountV2, error) {
+	if err := c.UpdateTokenContext(ctx, "api"); err != nil && c.Debug {
 		log.Printf("UpdateToken(api) warning: %v", err)
 	}
 
-	// Set header for account info (Python: Schwab-Client-Ids required for HoldingV2 in some cases)
-	if acc, ok := c.Headers["schwab-client-account"]; ok {
-		c.Headers["Schwab-Client-Ids"] = acc
-	} else if len(c.AccountIDs) > 0 {
-		c.Headers["Schwab-Client-Ids"] = c.AccountIDs[0]
+	// Python: Schwab-Client-Ids required for HoldingV2 in some cases; send one account per request.
+	ids := c.accountIDs()
+	if len(ids) == 0 {
+		if acc, ok := c.header("schwab-client-account"); ok {
+			ids = []string{acc}
+		}
 	}
-
-	req, err := http.NewRequest("GET", PositionsV2Url, nil)
-	if err != nil {
-		return nil, err
+	if len(ids) == 0 {
+		return c.getHoldings(ctx, "")
 	}
 
-	for k, v := range c.Headers {
-		req.Header.Set(k, v)
+	accounts := make(map[int64]AccountV2)
+	failures := make(map[string]error)
+	for _, accountID := range ids {
+		if err := ctx.Err(); err != nil {
+			return accounts, err
+		}
+		holdings, err := c.getHoldings(ctx, accountID)
+		if err != nil {
+			failures[accountID] = err
+			continue
+		}
+		for id, acc := range holdings {
+			accounts[id] = acc
+		}
+	}
+	if len(failures) > 0 {
+		return accounts, &AccountsError{Failures: failures}
 	}
+	return accounts, nil
+}
 
-	resp, err := c.HttpClient.Do(req)
+// getHoldings fetches HoldingV2 for one account; an empty accountID omits Schwab-Client-Ids.
+func (c *Client) getHoldings(ctx context.Context, accountID string) (map[int64]AccountV2, error) {
+	var extra map[string]string
+	if accountID != "" {
+		extra = map[string]string{"Schwab-Client-Ids": accountID}
+	}
+	u := c.endpoint(PositionsV2Url)
+	status, body, err := c.do(ctx, "GET", u, nil, extra)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
-		msg := string(body)
-		if len(msg) > 500 {
-			msg = msg[:500] + "..."
-		}
-		return nil, fmt.Errorf("API request failed with status: %d: %s", resp.StatusCode, msg)
+	if status != 200 {
+		return nil, newAPIError("GET", u, status, body)
 	}
 
 	var data AccountInfoV2Response
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, err
 	}
 
@@ -57,15 +83,47 @@ func (c *Client) GetAccountInfo() (map[int64]AccountV2, error) {
 		id, _ := strconv.ParseInt(acc.AccountID, 10, 64)
 		accounts[id] = acc
 	}
-
 	return accounts, nil
 }
 
+// AccountsError reports the accounts whose holdings could not be retrieved, keyed by account ID.
+type AccountsError struct {
+	Failures map[string]error
+}
+
+func (e *AccountsError) Error() string {
+	ids := make([]string, 0, len(e.Failures))
+	for id := range e.Failures {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("account %s: %v", id, e.Failures[id]))
+	}
+	return "failed to get holdings for " + strings.Join(parts, "; ")
+}
+
+// Unwrap exposes the per-account errors to errors.Is and errors.As.
+func (e *AccountsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, err := range e.Failures {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
 // GetAccountInfoV2 returns account info in the same shape as Python schwab-api get_account_info_v2()
 // for 1:1 porting: map[accountID] -> { account_value, positions: [{ symbol, market_value, quantity }] }.
+// Partial failures are reported like GetAccountInfo.
 func (c *Client) GetAccountInfoV2() (map[string]AccountInfoV2Compat, error) {
-	raw, err := c.GetAccountInfo()
-	if err != nil {
+	return c.GetAccountInfoV2Context(context.Background())
+}
+
+// GetAccountInfoV2Context is like GetAccountInfoV2 but uses ctx for cancellation and deadlines.
+func (c *Client) GetAccountInfoV2Context(ctx context.Context) (map[string]AccountInfoV2Compat, error) {
+	raw, err := c.GetAccountInfoContext(ctx)
+	if err != nil && raw == nil {
 		return nil, err
 	}
 	out := make(map[string]AccountInfoV2Compat)
@@ -86,167 +144,103 @@ func (c *Client) GetAccountInfoV2() (map[string]AccountInfoV2Compat, error) {
 		}
 		out[accID] = compat
 	}
-	return out, nil
+	return out, err
 }
 
-// TradeV2 is the same as Trade; name matches Python schwab-api trade_v2() for 1:1 porting.
+// TradeV2 is Trade returning the messages from the last step and whether it succeeded;
+// the name and return values match Python schwab-api trade_v2() for 1:1 porting.
 func (c *Client) TradeV2(ticker, side string, qty float64, accountID string, dryRun bool) ([]string, bool, error) {
-	return c.Trade(ticker, side, qty, accountID, dryRun)
+	return c.TradeV2Context(context.Background(), ticker, side, qty, accountID, dryRun)
 }
 
-// Trade executes or verifies a trade.
+// TradeV2Context is like TradeV2 but uses ctx for cancellation and deadlines.
+func (c *Client) TradeV2Context(ctx context.Context, ticker, side string, qty float64, accountID string, dryRun bool) ([]string, bool, error) {
+	result, err := c.TradeContext(ctx, ticker, side, qty, accountID, dryRun)
+	if result.VerifyMessages == nil {
+		return nil, false, err
+	}
+	return result.Messages(), err == nil && result.succeeded(), err
+}
+
+// Trade executes or verifies a market order.
 // ticker: Symbol to trade
 // side: "Buy" or "Sell"
 // qty: Quantity
 // accountId: Account ID
 // dryRun: If true, only verifies the order
-func (c *Client) Trade(ticker, side string, qty float64, accountId string, dryRun bool) ([]string, bool, error) {
-	if side != "Buy" && side != "Sell" {
-		return nil, false, fmt.Errorf("side must be 'Buy' or 'Sell'")
-	}
-
-	buySellCode := "49"
-	if side == "Sell" {
-		buySellCode = "50"
-	}
-
-	c.UpdateToken("update")
-
-	// Construct request body
-	requestBody := map[string]interface{}{
-		"UserContext": map[string]interface{}{
-			"AccountId":    accountId,
-			"AccountColor": 0,
-		},
-		"OrderStrategy": map[string]interface{}{
-			"PrimarySecurityType": 46, // Stock
-			"CostBasisRequest": map[string]interface{}{
-				"costBasisMethod":        "FIFO",
-				"defaultCostBasisMethod": "FIFO",
-			},
-			"OrderType":         "49", // Market
-			"LimitPrice":        "0",
-			"StopPrice":         "0",
-			"Duration":          "48", // Day
-			"AllNoneIn":         false,
-			"DoNotReduceIn":     false,
-			"OrderStrategyType": 1,
-			"OrderLegs": []map[string]interface{}{
-				{
-					"Quantity":       fmt.Sprintf("%f", qty),
-					"LeavesQuantity": fmt.Sprintf("%f", qty),
-					"Instrument":     map[string]interface{}{"Symbol": ticker},
-					"SecurityType":   46,
-					"Instruction":    buySellCode,
-				},
-			},
-		},
-		"OrderProcessingControl": 1, // Verification
-	}
-
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return nil, false, err
-	}
-
-	// Make request
-	req, err := http.NewRequest("POST", OrderVerificationV2Url, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return nil, false, err
-	}
-
-	for k, v := range c.Headers {
-		req.Header.Set(k, v)
-	}
-	// Required header
-	req.Header.Set("schwab-resource-version", "1.0")
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.HttpClient.Do(req)
-	if err != nil {
-		return nil, false, err
-	}
-	defer resp.Body.Close()
+func (c *Client) Trade(ticker, side string, qty float64, accountId string, dryRun bool) (TradeResult, error) {
+	return c.TradeContext(context.Background(), ticker, side, qty, accountId, dryRun)
+}
 
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	if c.Debug {
-		log.Printf("Verification Response: %s", string(bodyBytes))
-	}
+// TradeContext is like Trade but uses ctx for cancellation and deadlines.
+func (c *Client) TradeContext(ctx context.Context, ticker, side string, qty float64, accountId string, dryRun bool) (TradeResult, error) {
+	return c.PlaceOrderContext(ctx, OrderRequest{
+		AccountID: accountId,
+		Symbol:    ticker,
+		Side:      side,
+		Quantity:  qty,
+		Type:      OrderTypeMarket,
+		DryRun:    dryRun,
+	})
+}
 
-	if resp.StatusCode != 200 {
-		return []string{string(bodyBytes)}, false, nil
+// do sends a request with a snapshot of the session headers plus extra, JSON-encoding body when non-nil.
+// It returns the status code and the full response body. If the session is rejected and
+// c.Reauth is set, it re-authenticates and retries the request once.
+func (c *Client) do(ctx context.Context, method, url string, body interface{}, extra map[string]string) (int, []byte, error) {
+	gen := c.generation()
+	status, respBody, err := c.doOnce(ctx, method, url, body, extra)
+	if err != nil || !needsReauth(status) || !c.canReauth(ctx) {
+		return status, respBody, err
 	}
-
-	var verifyResp OrderVerificationResponse
-	if err := json.Unmarshal(bodyBytes, &verifyResp); err != nil {
-		return nil, false, err
+	if err := c.reauthenticate(ctx, gen); err != nil {
+		return status, respBody, err
 	}
+	return c.doOnce(ctx, method, url, body, extra)
+}
 
-	messages := []string{}
-	for _, msg := range verifyResp.OrderStrategy.OrderMessages {
-		messages = append(messages, msg.Message)
+// doOnce is do without re-authentication.
+func (c *Client) doOnce(ctx context.Context, method, url string, body interface{}, extra map[string]string) (int, []byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reqBody = bytes.NewReader(jsonBody)
 	}
 
-	// Check return codes (0, 10 are usually success/warning)
-	validCodes := map[int]bool{0: true, 10: true}
-	if !validCodes[verifyResp.OrderStrategy.OrderReturnCode] {
-		return messages, false, nil
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return 0, nil, err
 	}
-
-	if dryRun {
-		return messages, true, nil
+	for k, v := range c.requestHeaders() {
+		req.Header.Set(k, v)
 	}
-
-	// Proceed to execution
-	orderId := verifyResp.OrderStrategy.OrderId
-	if len(verifyResp.OrderStrategy.OrderLegs) > 0 {
-		leg := verifyResp.OrderStrategy.OrderLegs[0]
-		// Need to update ItemIssueId
-		if legs, ok := requestBody["OrderStrategy"].(map[string]interface{})["OrderLegs"].([]map[string]interface{}); ok {
-			legs[0]["Instrument"].(map[string]interface{})["ItemIssueId"] = leg.SchwabSecurityId
-		}
+	for k, v := range extra {
+		req.Header.Set(k, v)
 	}
-
-	// Update for execution
-	requestBody["UserContext"].(map[string]interface{})["CustomerId"] = 0
-	requestBody["OrderStrategy"].(map[string]interface{})["OrderId"] = orderId
-	requestBody["OrderProcessingControl"] = 2 // Execution
-
-	execBody, _ := json.Marshal(requestBody)
-
-	c.UpdateToken("update")
-
-	reqExec, _ := http.NewRequest("POST", OrderVerificationV2Url, bytes.NewBuffer(execBody))
-	for k, v := range c.Headers {
-		reqExec.Header.Set(k, v)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
-	reqExec.Header.Set("schwab-resource-version", "1.0")
-	reqExec.Header.Set("Content-Type", "application/json")
 
-	respExec, err := c.HttpClient.Do(reqExec)
+	resp, err := c.HttpClient.Do(req)
 	if err != nil {
-		return nil, false, err
+		return 0, nil, err
 	}
-	defer respExec.Body.Close()
-
-	execBytes, _ := io.ReadAll(respExec.Body)
-	if c.Debug {
-		log.Printf("Execution Response: %s", string(execBytes))
-	}
-
-	// Parse again
-	// Re-using struct as response is similar
-	var execResp OrderVerificationResponse
-	json.Unmarshal(execBytes, &execResp)
+	defer resp.Body.Close()
 
-	execMessages := []string{}
-	for _, msg := range execResp.OrderStrategy.OrderMessages {
-		execMessages = append(execMessages, msg.Message)
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err
 	}
+	return resp.StatusCode, respBody, nil
+}
 
-	if validCodes[execResp.OrderStrategy.OrderReturnCode] {
-		return execMessages, true, nil
+// truncate shortens a response body for inclusion in an error message.
+func truncate(msg string) string {
+	if len(msg) > 500 {
+		return msg[:500] + "..."
 	}
-
-	return execMessages, false, nil
+	return msg
 }
@@ -1,27 +1,107 @@
 package schwab
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 )
 
 func TestTrade_InvalidSide(t *testing.T) {
 	c := NewClient(false)
-	_, ok, err := c.Trade("AAPL", "INVALID", 1, "123", true)
+	result, err := c.Trade("AAPL", "INVALID", 1, "123", true)
 	if err == nil {
 		t.Fatal("expected error for invalid side")
 	}
-	if ok {
-		t.Error("expected ok false for invalid side")
+	if result.Verified || result.Placed {
+		t.Error("expected unverified result for invalid side")
 	}
 	if err.Error() != "side must be 'Buy' or 'Sell'" {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
 
+func TestTradeV2_Parity(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orderStrategy":{"orderId":1,"orderReturnCode":0,"orderMessages":[{"message":"verified"}]}}`))
+	})
+	messages, ok, err := c.TradeV2("AAPL", "Buy", 1, "123", true)
+	if err != nil || !ok || len(messages) != 1 || messages[0] != "verified" {
+		t.Errorf("TradeV2 = %v, %v, %v", messages, ok, err)
+	}
+
+	messages, ok, err = c.TradeV2("AAPL", "Hold", 1, "123", true)
+	if err == nil || ok || messages != nil {
+		t.Errorf("TradeV2 invalid side = %v, %v, %v", messages, ok, err)
+	}
+}
+
 func TestTrade_EmptySide(t *testing.T) {
 	c := NewClient(false)
-	_, _, err := c.Trade("AAPL", "", 1, "123", true)
+	_, err := c.Trade("AAPL", "", 1, "123", true)
 	if err == nil {
 		t.Fatal("expected error for empty side")
 	}
 }
+
+func TestGetAccountInfo_AllAccounts(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch id := r.Header.Get("Schwab-Client-Ids"); id {
+		case "111", "222":
+			fmt.Fprintf(w, `{"accounts":[{"accountId":%q,"totals":{"accountValue":100}}]}`, id)
+		default:
+			http.Error(w, "bad account", http.StatusBadRequest)
+		}
+	})
+	c.AccountIDs = []string{"111", "999", "222"}
+
+	accounts, err := c.GetAccountInfo()
+	var accErr *AccountsError
+	if !errors.As(err, &accErr) {
+		t.Fatalf("expected *AccountsError, got %v", err)
+	}
+	if len(accErr.Failures) != 1 || accErr.Failures["999"] == nil {
+		t.Errorf("unexpected failures: %v", accErr.Failures)
+	}
+	if len(accounts) != 2 || accounts[111].AccountID != "111" || accounts[222].AccountID != "222" {
+		t.Errorf("unexpected accounts: %v", accounts)
+	}
+	if _, ok := c.Headers["Schwab-Client-Ids"]; ok {
+		t.Error("GetAccountInfo should not store Schwab-Client-Ids in Client.Headers")
+	}
+
+	compat, err := c.GetAccountInfoV2()
+	if err == nil || len(compat) != 2 {
+		t.Errorf("GetAccountInfoV2: %d accounts, err %v", len(compat), err)
+	}
+}
+
+func TestGetAccountInfoContext_Deadline(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Hang until the client gives up.
+		<-r.Context().Done()
+	})
+	c.AccountIDs = []string{"111", "222"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetAccountInfoContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("GetAccountInfoContext took %v after the deadline", elapsed)
+	}
+}
+
+func TestLoginContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Returns before starting Playwright.
+	if err := NewClient(false).LoginContext(ctx, "user", "pass", ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
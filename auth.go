@@ -1,10 +1,10 @@
 package schwab
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"regexp"
 	"strings"
 	"time"
@@ -14,48 +14,111 @@ import (
 )
 
 // Login performs the login flow using Playwright (same approach as Python schwab-api / MaxxRK fork).
+// It is Authenticate with a PlaywrightAuthenticator configured from c.LoginOptions.
 func (c *Client) Login(username, password, totpSecret string) error {
-	var fullPassword = password
-	if totpSecret != "" {
-		code, err := totp.GenerateCode(totpSecret, time.Now())
+	return c.LoginContext(context.Background(), username, password, totpSecret)
+}
+
+// LoginContext is like Login but uses ctx for cancellation and deadlines.
+// Cancelling ctx closes the browser, which aborts any pending step of the login flow.
+func (c *Client) LoginContext(ctx context.Context, username, password, totpSecret string) error {
+	return c.AuthenticateContext(ctx, c.playwrightAuthenticator(username, password, totpSecret))
+}
+
+// playwrightAuthenticator returns a PlaywrightAuthenticator for the credentials using the client's settings.
+func (c *Client) playwrightAuthenticator(username, password, totpSecret string) *PlaywrightAuthenticator {
+	return &PlaywrightAuthenticator{
+		Username:   username,
+		Password:   password,
+		TOTPSecret: totpSecret,
+		Options:    c.LoginOptions,
+		Endpoints:  c.Endpoints,
+		Debug:      c.Debug,
+	}
+}
+
+// PlaywrightAuthenticator logs in through a Chromium browser driven by Playwright and captures
+// the headers, cookies and bearer token the trade page sends.
+type PlaywrightAuthenticator struct {
+	Username   string
+	Password   string
+	TOTPSecret string
+	Options    LoginOptions
+	// Endpoints overrides the hosts the browser visits and reads cookies from.
+	Endpoints Endpoints
+	Debug     bool
+}
+
+// Authenticate runs the browser login flow.
+func (a *PlaywrightAuthenticator) Authenticate(ctx context.Context) (_ Session, err error) {
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			err = fmt.Errorf("login aborted: %w", ctx.Err())
+		}
+	}()
+	if err := ctx.Err(); err != nil {
+		return Session{}, err
+	}
+
+	var fullPassword = a.Password
+	if a.TOTPSecret != "" {
+		code, err := totp.GenerateCode(a.TOTPSecret, time.Now())
 		if err != nil {
-			return fmt.Errorf("failed to generate TOTP code: %w", err)
+			return Session{}, fmt.Errorf("failed to generate TOTP code: %w", err)
 		}
-		fullPassword = password + code
+		fullPassword = a.Password + code
 	}
 
+	opts := a.Options.withDefaults()
+
 	pw, err := playwright.Run()
 	if err != nil {
-		return fmt.Errorf("failed to start Playwright: %w", err)
+		return Session{}, fmt.Errorf("failed to start Playwright: %w", err)
 	}
 	defer pw.Stop()
 
-	browser, err := pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
-		Headless: playwright.Bool(false),
-		Args: []string{
-			"--disable-blink-features=AutomationControlled",
-			"--disable-automation",
-		},
-	})
+	browser, err := pw.Chromium.Launch(opts.launchOptions())
 	if err != nil {
-		return fmt.Errorf("failed to launch browser: %w", err)
+		return Session{}, fmt.Errorf("failed to launch browser: %w", err)
 	}
 	defer browser.Close()
 
-	context, err := browser.NewContext(playwright.BrowserNewContextOptions{
-		UserAgent: playwright.String("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36"),
-		Viewport:  &playwright.Size{Width: 1920, Height: 1080},
+	// Closing the browser makes any pending Playwright call return promptly.
+	stopAbort := context.AfterFunc(ctx, func() { browser.Close() })
+	defer stopAbort()
+
+	browserCtx, err := browser.NewContext(playwright.BrowserNewContextOptions{
+		UserAgent: playwright.String(opts.UserAgent),
+		Viewport:  &playwright.Size{Width: opts.ViewportWidth, Height: opts.ViewportHeight},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create context: %w", err)
+		return Session{}, fmt.Errorf("failed to create context: %w", err)
 	}
-	defer context.Close()
+	defer browserCtx.Close()
 
-	page, err := context.NewPage()
+	if opts.DiagnosticsDir != "" {
+		err = browserCtx.Tracing().Start(playwright.TracingStartOptions{
+			Screenshots: playwright.Bool(true),
+			Snapshots:   playwright.Bool(true),
+		})
+		if err != nil && a.Debug {
+			log.Printf("Could not start Playwright trace: %v", err)
+		}
+	}
+
+	page, err := browserCtx.NewPage()
 	if err != nil {
-		return fmt.Errorf("failed to create page: %w", err)
+		return Session{}, fmt.Errorf("failed to create page: %w", err)
 	}
 
+	// Classify the failure from what the page shows and save diagnostics (skipped when cancelled,
+	// since the browser is already closed).
+	defer func() {
+		if err != nil && ctx.Err() == nil {
+			err = a.loginFailure(page, browserCtx, opts, err)
+		}
+	}()
+
 	// Capture all headers from balancespositions request (like Python: self.headers = await route.request.all_headers()).
 	// The browser sends Authorization, Schwab-ChannelCode, and other headers required by the API.
 	headersChan := make(chan map[string]string, 1)
@@ -72,106 +135,115 @@ func (c *Client) Login(username, password, totpSecret string) error {
 		_ = route.Continue()
 	})
 	if err != nil {
-		return fmt.Errorf("failed to set route: %w", err)
+		return Session{}, fmt.Errorf("failed to set route: %w", err)
 	}
 
-	if c.Debug {
+	if a.Debug {
 		log.Println("Navigating to Schwab login page...")
 	}
 
-	_, err = page.Goto(HomepageUrl, playwright.PageGotoOptions{Timeout: playwright.Float(60000)})
+	_, err = page.Goto(a.Endpoints.resolve(HomepageUrl), playwright.PageGotoOptions{Timeout: millis(opts.NavigationTimeout)})
 	if err != nil {
-		return fmt.Errorf("failed to navigate to login page: %w", err)
+		return Session{}, fmt.Errorf("failed to navigate to login page: %w", err)
 	}
 
-	_, err = page.WaitForSelector("iframe#schwablmslogin", playwright.PageWaitForSelectorOptions{Timeout: playwright.Float(30000)})
+	_, err = page.WaitForSelector("iframe#schwablmslogin", playwright.PageWaitForSelectorOptions{Timeout: millis(opts.SelectorTimeout)})
 	if err != nil {
-		return fmt.Errorf("failed to wait for login iframe: %w", err)
+		return Session{}, fmt.Errorf("failed to wait for login iframe: %w", err)
 	}
 
 	// Use FrameLocator so we target the iframe by selector; page.Frame(Name) can be nil until frame is attached.
 	fl := page.FrameLocator("iframe#schwablmslogin")
 
-	if c.Debug {
+	if a.Debug {
 		log.Println("Entering credentials...")
 	}
 
 	// Match Python: select_option(landingPageOptions, index=3) for Trade (two selects in iframe; use first)
 	_, err = fl.Locator("select#landingPageOptions").First().SelectOption(playwright.SelectOptionValues{Indexes: &[]int{3}})
 	if err != nil {
-		return fmt.Errorf("failed to select Trade: %w", err)
+		return Session{}, fmt.Errorf("failed to select Trade: %w", err)
 	}
 
 	loginSel := `[placeholder="Login ID"]`
 	passSel := `[placeholder="Password"]`
 
-	err = fl.Locator(loginSel).Fill(username)
+	err = fl.Locator(loginSel).Fill(a.Username)
 	if err != nil {
-		return fmt.Errorf("failed to fill login ID: %w", err)
+		return Session{}, fmt.Errorf("failed to fill login ID: %w", err)
 	}
 	err = fl.Locator(loginSel).Press("Tab")
 	if err != nil {
-		return fmt.Errorf("failed to tab to password: %w", err)
+		return Session{}, fmt.Errorf("failed to tab to password: %w", err)
 	}
 	err = fl.Locator(passSel).Fill(fullPassword)
 	if err != nil {
-		return fmt.Errorf("failed to fill password: %w", err)
+		return Session{}, fmt.Errorf("failed to fill password: %w", err)
 	}
 	err = fl.Locator(passSel).Press("Enter")
 	if err != nil {
-		return fmt.Errorf("failed to submit login: %w", err)
+		return Session{}, fmt.Errorf("failed to submit login: %w", err)
 	}
 
-	// Wait 5 seconds after login then refresh so the session is fully established
-	page.WaitForTimeout(5000)
-	_, err = page.Reload(playwright.PageReloadOptions{Timeout: playwright.Float(30000)})
+	a.report(opts, LoginStateCredentialsSubmitted)
+
+	// Wait after login (5 seconds by default), completing any SMS/push verification Schwab asks for,
+	// then refresh so the session is fully established
+	ch, err := detectChallenge(ctx, page, fl, opts.PostLoginDelay)
+	if err != nil {
+		return Session{}, err
+	}
+	if err := a.handleChallenge(ctx, page, fl, opts, ch); err != nil {
+		return Session{}, err
+	}
+	_, err = page.Reload(playwright.PageReloadOptions{Timeout: millis(opts.ReloadTimeout)})
 	if err != nil {
-		return fmt.Errorf("refresh after login: %w", err)
+		return Session{}, fmt.Errorf("refresh after login: %w", err)
 	}
 
-	if c.Debug {
+	if a.Debug {
 		log.Println("Waiting for login to complete and token capture...")
 	}
 
 	var capturedHeaders map[string]string
 	select {
 	case capturedHeaders = <-headersChan:
-		c.BearerToken = capturedHeaders["authorization"]
-		if c.Debug {
+		if a.Debug {
 			log.Println("Captured Bearer Token!")
 		}
-	case <-time.After(60 * time.Second):
-		return fmt.Errorf("timed out waiting for authorization header")
+	case <-time.After(opts.TokenTimeout):
+		return Session{}, fmt.Errorf("timed out waiting for authorization header")
+	case <-ctx.Done():
+		return Session{}, ctx.Err()
 	}
 
 	// Match Python: wait for app/trade and #_txtSymbol before capturing cookies
-	err = page.WaitForURL(regexp.MustCompile(`app/trade`), playwright.PageWaitForURLOptions{Timeout: playwright.Float(60000)})
+	err = page.WaitForURL(tradeURLRe, playwright.PageWaitForURLOptions{Timeout: millis(opts.TradePageTimeout)})
 	if err != nil {
-		return fmt.Errorf("wait for trade URL: %w", err)
+		return Session{}, fmt.Errorf("wait for trade URL: %w", err)
 	}
-	_, err = page.WaitForSelector("#_txtSymbol", playwright.PageWaitForSelectorOptions{Timeout: playwright.Float(30000)})
+	_, err = page.WaitForSelector("#_txtSymbol", playwright.PageWaitForSelectorOptions{Timeout: millis(opts.SelectorTimeout)})
 	if err != nil {
-		return fmt.Errorf("wait for trade page: %w", err)
+		return Session{}, fmt.Errorf("wait for trade page: %w", err)
 	}
 	// Refresh the trade page so session/cookies are fully established (same as manual refresh).
-	_, err = page.Reload(playwright.PageReloadOptions{Timeout: playwright.Float(30000)})
+	_, err = page.Reload(playwright.PageReloadOptions{Timeout: millis(opts.ReloadTimeout)})
 	if err != nil {
-		return fmt.Errorf("refresh trade page: %w", err)
+		return Session{}, fmt.Errorf("refresh trade page: %w", err)
 	}
-	_, err = page.WaitForSelector("#_txtSymbol", playwright.PageWaitForSelectorOptions{Timeout: playwright.Float(30000)})
+	_, err = page.WaitForSelector("#_txtSymbol", playwright.PageWaitForSelectorOptions{Timeout: millis(opts.SelectorTimeout)})
 	if err != nil {
-		return fmt.Errorf("wait for trade page after refresh: %w", err)
+		return Session{}, fmt.Errorf("wait for trade page after refresh: %w", err)
+	}
+	// let cookies settle after refresh
+	if err := sleepContext(ctx, opts.CookieSettleDelay); err != nil {
+		return Session{}, err
 	}
-	page.WaitForTimeout(1500) // let cookies settle after refresh
 
 	// Cookies for Schwab API domains only (avoid 431 Request Header Fields Too Large from sending every cookie).
 	byName := make(map[string]string)
-	for _, u := range []string{
-		"https://www.schwab.com",
-		"https://client.schwab.com",
-		"https://ausgateway.schwab.com",
-	} {
-		cookies, err := context.Cookies(u)
+	for _, u := range a.Endpoints.cookieURLs() {
+		cookies, err := browserCtx.Cookies(u)
 		if err != nil {
 			continue
 		}
@@ -185,48 +257,78 @@ func (c *Client) Login(username, password, totpSecret string) error {
 	}
 	cookiesStr := strings.Join(parts, "; ")
 	if cookiesStr == "" {
-		return fmt.Errorf("no session cookies captured for Schwab domains")
+		return Session{}, fmt.Errorf("no session cookies captured for Schwab domains")
 	}
 
-	// Use captured request headers (includes Schwab-ChannelCode, etc.); override with our Cookie and Bearer.
-	c.Headers = make(map[string]string)
-	for k, v := range capturedHeaders {
-		c.Headers[k] = v
-	}
-	c.Headers["Authorization"] = c.BearerToken
-	c.Headers["Cookie"] = cookiesStr
+	// Use captured request headers (includes Schwab-ChannelCode, etc.); the client adds our Cookie and Bearer.
+	return Session{
+		Headers:     capturedHeaders,
+		BearerToken: capturedHeaders["authorization"],
+		Cookies:     cookiesStr,
+		CapturedAt:  time.Now(),
+	}, nil
+}
 
-	return nil
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-// updateToken updates the bearer token if expired.
+// UpdateToken refreshes the bearer token for the given scope ("api" or "update").
+// If the refresh is rejected and c.Reauth is set, it re-authenticates and tries once more.
 func (c *Client) UpdateToken(tokenType string) error {
-	url := fmt.Sprintf("https://client.schwab.com/api/auth/authorize/scope/%s", tokenType)
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
+	return c.UpdateTokenContext(context.Background(), tokenType)
+}
+
+// UpdateTokenContext is like UpdateToken but uses ctx for cancellation and deadlines.
+func (c *Client) UpdateTokenContext(ctx context.Context, tokenType string) error {
+	gen := c.generation()
+	status, err := c.updateToken(ctx, tokenType)
+	// Only a rejected session (401/403) needs replacing; network errors, rate limits, server
+	// errors and undecodable responses are returned as they are.
+	if err == nil || !needsReauth(status) || !c.canReauth(ctx) {
 		return err
 	}
-	for k, v := range c.Headers {
-		req.Header.Set(k, v)
+	if c.Debug {
+		log.Printf("%v. Re-logging in...", err)
 	}
-	resp, err := c.HttpClient.Do(req)
-	if err != nil {
+	if err := c.reauthenticate(ctx, gen); err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
+	_, err = c.updateToken(ctx, tokenType)
+	return err
+}
+
+// updateToken performs one token refresh, returning the HTTP status (0 if no response was received).
+func (c *Client) updateToken(ctx context.Context, tokenType string) (int, error) {
+	url := c.endpoint(AuthorizeScopeUrl) + tokenType
+	status, body, err := c.doOnce(ctx, "GET", url, nil, nil)
+	if err != nil {
+		return status, err
+	}
+	if status != 200 {
 		if c.Debug {
-			log.Printf("Token update failed with status: %d. Re-logging in...", resp.StatusCode)
+			log.Printf("Token update failed with status: %d", status)
 		}
-		return fmt.Errorf("failed to update token, status: %d", resp.StatusCode)
+		apiErr := newAPIError("GET", url, status, body)
+		if needsReauth(status) {
+			apiErr.kind = ErrSessionExpired
+		}
+		return status, apiErr
 	}
 	var result struct {
 		Token string `json:"token"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return err
+	if err := json.Unmarshal(body, &result); err != nil {
+		return status, err
 	}
-	c.BearerToken = "Bearer " + result.Token
-	c.Headers["Authorization"] = c.BearerToken
-	return nil
+	c.setBearerToken("Bearer " + result.Token)
+	return status, nil
 }
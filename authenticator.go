@@ -0,0 +1,142 @@
+package schwab
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Authenticator produces the session (headers, cookies and bearer token) a Client uses for API calls.
+// PlaywrightAuthenticator logs in with a browser; SessionStoreAuthenticator and ManualAuthenticator
+// supply a session obtained elsewhere, so no browser is needed.
+type Authenticator interface {
+	Authenticate(ctx context.Context) (Session, error)
+}
+
+// Authenticate replaces the client's session with the one returned by auth.
+// Accounts are discovered afterwards when c.DiscoverAccounts is set, as with Login.
+func (c *Client) Authenticate(auth Authenticator) error {
+	return c.AuthenticateContext(context.Background(), auth)
+}
+
+// AuthenticateContext is like Authenticate but uses ctx for cancellation and deadlines.
+func (c *Client) AuthenticateContext(ctx context.Context, auth Authenticator) error {
+	s, err := auth.Authenticate(ctx)
+	if err != nil {
+		return err
+	}
+	if s.BearerToken == "" {
+		return fmt.Errorf("authenticator returned no bearer token")
+	}
+	if s.CapturedAt.IsZero() {
+		s.CapturedAt = time.Now()
+	}
+	c.RestoreSession(s)
+
+	if c.DiscoverAccounts && len(c.accountIDs()) == 0 {
+		if err := c.discoverAccounts(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SessionStoreAuthenticator imports a session saved earlier, e.g. by SaveSession on another machine:
+//
+//	client.Authenticate(schwab.SessionStoreAuthenticator{Store: schwab.FileSessionStore{Path: "session.json"}})
+//
+// It does not check that the session is still valid; use LoginOrRestore for that.
+type SessionStoreAuthenticator struct {
+	Store SessionStore
+}
+
+// Authenticate loads the session from the store.
+func (a SessionStoreAuthenticator) Authenticate(ctx context.Context) (Session, error) {
+	if err := ctx.Err(); err != nil {
+		return Session{}, err
+	}
+	s, err := a.Store.Load()
+	if err != nil {
+		return Session{}, err
+	}
+	if s.BearerToken == "" {
+		return Session{}, fmt.Errorf("saved session has no bearer token")
+	}
+	return s, nil
+}
+
+// ManualAuthenticator builds a session from a bearer token and cookie header copied out of a
+// logged-in browser (developer tools, Network tab, any client.schwab.com API request).
+// If BearerToken or Cookies is empty and In is set, the missing values are prompted for on Out and read from In.
+type ManualAuthenticator struct {
+	BearerToken string
+	Cookies     string
+	// Headers are any other request headers to send, e.g. Schwab-ChannelCode.
+	Headers map[string]string
+
+	In  io.Reader
+	Out io.Writer
+}
+
+// Authenticate returns the pasted session, prompting for missing values when In is set.
+func (a *ManualAuthenticator) Authenticate(ctx context.Context) (Session, error) {
+	if err := ctx.Err(); err != nil {
+		return Session{}, err
+	}
+	token, cookies := a.BearerToken, a.Cookies
+	if (token == "" || cookies == "") && a.In != nil {
+		r := bufio.NewReader(a.In)
+		var err error
+		if token == "" {
+			if token, err = a.prompt(r, "Bearer token: "); err != nil {
+				return Session{}, err
+			}
+		}
+		if cookies == "" {
+			if cookies, err = a.prompt(r, "Cookie header: "); err != nil {
+				return Session{}, err
+			}
+		}
+	}
+	if token == "" {
+		return Session{}, fmt.Errorf("no bearer token provided")
+	}
+	if cookies == "" {
+		return Session{}, fmt.Errorf("no cookies provided")
+	}
+
+	headers := make(map[string]string, len(a.Headers))
+	for k, v := range a.Headers {
+		headers[k] = v
+	}
+	return Session{
+		Headers:     headers,
+		BearerToken: normalizeBearer(token),
+		Cookies:     strings.TrimPrefix(cookies, "Cookie: "),
+		CapturedAt:  time.Now(),
+	}, nil
+}
+
+// prompt writes label to a.Out and reads one trimmed line from r.
+func (a *ManualAuthenticator) prompt(r *bufio.Reader, label string) (string, error) {
+	if a.Out != nil {
+		fmt.Fprint(a.Out, label)
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read %s%w", strings.ToLower(label), err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// normalizeBearer accepts a token with or without the "Authorization:" and "Bearer" prefixes.
+func normalizeBearer(token string) string {
+	token = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(token), "Authorization:"))
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = token[7:]
+	}
+	return "Bearer " + strings.TrimSpace(token)
+}
@@ -0,0 +1,125 @@
+package schwab
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type staticAuthenticator struct {
+	session Session
+	err     error
+	calls   int
+}
+
+func (a *staticAuthenticator) Authenticate(ctx context.Context) (Session, error) {
+	a.calls++
+	return a.session, a.err
+}
+
+func TestAuthenticate_ConsumesSession(t *testing.T) {
+	var gotAuth, gotCookie, gotChannel string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotCookie = r.Header.Get("Cookie")
+		gotChannel = r.Header.Get("Schwab-ChannelCode")
+		w.Write([]byte(`{"accounts":[]}`))
+	})
+	auth := &staticAuthenticator{session: Session{
+		Headers:     map[string]string{"Schwab-ChannelCode": "IO"},
+		BearerToken: "Bearer abc",
+		Cookies:     "a=1",
+	}}
+	if err := c.Authenticate(auth); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if c.BearerToken != "Bearer abc" || c.Session().CapturedAt.IsZero() {
+		t.Errorf("session not applied: %+v", c.Session())
+	}
+	if _, err := c.GetAccountInfo(); err != nil {
+		t.Fatalf("GetAccountInfo: %v", err)
+	}
+	// The token is refreshed by the test server before the holdings request.
+	if gotAuth != "Bearer test-token" || gotCookie != "a=1" || gotChannel != "IO" {
+		t.Errorf("headers = %q, %q, %q", gotAuth, gotCookie, gotChannel)
+	}
+}
+
+func TestAuthenticate_Errors(t *testing.T) {
+	c := NewClient(false)
+	wantErr := errors.New("boom")
+	if err := c.Authenticate(&staticAuthenticator{err: wantErr}); !errors.Is(err, wantErr) {
+		t.Errorf("err = %v, want %v", err, wantErr)
+	}
+	if err := c.Authenticate(&staticAuthenticator{session: Session{Cookies: "a=1"}}); err == nil {
+		t.Error("expected error for session without bearer token")
+	}
+}
+
+func TestSessionStoreAuthenticator(t *testing.T) {
+	store := &MemorySessionStore{}
+	if _, err := (SessionStoreAuthenticator{Store: store}).Authenticate(context.Background()); !errors.Is(err, ErrNoSession) {
+		t.Errorf("empty store: err = %v, want ErrNoSession", err)
+	}
+	store.Save(Session{BearerToken: "Bearer saved", Cookies: "b=2"})
+	c := NewClient(false)
+	if err := c.Authenticate(SessionStoreAuthenticator{Store: store}); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if c.BearerToken != "Bearer saved" || c.Headers["Cookie"] != "b=2" {
+		t.Errorf("session = %+v", c.Session())
+	}
+}
+
+func TestManualAuthenticator(t *testing.T) {
+	s, err := (&ManualAuthenticator{BearerToken: "Authorization: Bearer xyz", Cookies: "Cookie: c=3"}).Authenticate(context.Background())
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if s.BearerToken != "Bearer xyz" || s.Cookies != "c=3" {
+		t.Errorf("session = %+v", s)
+	}
+
+	var out bytes.Buffer
+	a := &ManualAuthenticator{In: strings.NewReader("  tok  \nd=4; e=5\n"), Out: &out}
+	s, err = a.Authenticate(context.Background())
+	if err != nil {
+		t.Fatalf("Authenticate with prompt: %v", err)
+	}
+	if s.BearerToken != "Bearer tok" || s.Cookies != "d=4; e=5" {
+		t.Errorf("prompted session = %+v", s)
+	}
+	if !strings.Contains(out.String(), "Bearer token: ") || !strings.Contains(out.String(), "Cookie header: ") {
+		t.Errorf("prompts = %q", out.String())
+	}
+
+	if _, err := (&ManualAuthenticator{BearerToken: "tok"}).Authenticate(context.Background()); err == nil {
+		t.Error("expected error without cookies")
+	}
+	if _, err := (&ManualAuthenticator{In: strings.NewReader("")}).Authenticate(context.Background()); err == nil {
+		t.Error("expected error on empty input")
+	}
+}
+
+func TestReauth_UsesAuthenticator(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"accounts":[{"accountId":"123"}]}`))
+	})
+	c.RestoreSession(Session{BearerToken: "Bearer stale", Cookies: "stale"})
+	auth := &staticAuthenticator{session: Session{BearerToken: "Bearer fresh", Cookies: "fresh"}}
+	c.Reauth = &ReauthPolicy{Authenticator: auth}
+
+	if _, err := c.GetAccountInfo(); err != nil {
+		t.Fatalf("GetAccountInfo: %v", err)
+	}
+	if auth.calls != 1 {
+		t.Errorf("authenticator called %d times, want 1", auth.calls)
+	}
+}
@@ -0,0 +1,195 @@
+package schwab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CancelStatus is the outcome of a cancel request.
+type CancelStatus string
+
+const (
+	CancelStatusCancelled     CancelStatus = "Cancelled"
+	CancelStatusAlreadyFilled CancelStatus = "AlreadyFilled"
+	CancelStatusRejected      CancelStatus = "Rejected"
+)
+
+// CancelResult describes what happened to one cancel request.
+type CancelResult struct {
+	OrderID  int64
+	Status   CancelStatus
+	Messages []string
+}
+
+// InstrumentType is Schwab's security type code for an order, sent as InstrumentType when cancelling.
+type InstrumentType int
+
+const (
+	InstrumentStock  InstrumentType = 46
+	InstrumentOption InstrumentType = 48
+)
+
+// occSymbolRe matches an OCC option symbol such as "AAPL  240315C00150000" (root padding optional).
+var occSymbolRe = regexp.MustCompile(`^[A-Z0-9.]{1,6} *\d{6}[CP]\d{8}$`)
+
+// instrumentTypeOf returns the instrument type of an order row: the SecurityType Schwab sent,
+// or else option for an OCC symbol and stock otherwise.
+func instrumentTypeOf(securityType int, symbol string) InstrumentType {
+	if securityType != 0 {
+		return InstrumentType(securityType)
+	}
+	if occSymbolRe.MatchString(strings.ToUpper(strings.TrimSpace(symbol))) {
+		return InstrumentOption
+	}
+	return InstrumentStock
+}
+
+// openOrderStatuses are the Order.Status values (lower-cased) that can still be cancelled.
+var openOrderStatuses = map[string]bool{"open": true, "working": true, "pending": true}
+
+// CancelOrder cancels a working stock order via CancelOrderV2Url (Python schwab-api cancel_order_v2()).
+// Like order placement, Schwab confirms the cancel first (OrderProcessingControl 1) and then applies it (2).
+// Use CancelOrderOfType for option orders.
+func (c *Client) CancelOrder(accountID string, orderID int64) (CancelResult, error) {
+	return c.CancelOrderOfTypeContext(context.Background(), accountID, orderID, InstrumentStock)
+}
+
+// CancelOrderContext is like CancelOrder but uses ctx for cancellation and deadlines.
+func (c *Client) CancelOrderContext(ctx context.Context, accountID string, orderID int64) (CancelResult, error) {
+	return c.CancelOrderOfTypeContext(ctx, accountID, orderID, InstrumentStock)
+}
+
+// CancelOrderOfType is CancelOrder for an order of the given instrument type, e.g. Order.InstrumentType
+// from GetOrders (Python cancel_order_v2(instrument_type=...)).
+func (c *Client) CancelOrderOfType(accountID string, orderID int64, instrumentType InstrumentType) (CancelResult, error) {
+	return c.CancelOrderOfTypeContext(context.Background(), accountID, orderID, instrumentType)
+}
+
+// CancelOrderOfTypeContext is like CancelOrderOfType but uses ctx for cancellation and deadlines.
+func (c *Client) CancelOrderOfTypeContext(ctx context.Context, accountID string, orderID int64, instrumentType InstrumentType) (CancelResult, error) {
+	result := CancelResult{OrderID: orderID, Status: CancelStatusRejected}
+
+	requestBody := map[string]interface{}{
+		"TypeOfOrder":           "0",
+		"OrderManagementSystem": "2",
+		"Orders": []map[string]interface{}{
+			{
+				"OrderId":         strconv.FormatInt(orderID, 10),
+				"IsLiveOrder":     true,
+				"InstrumentType":  int(instrumentType),
+				"CancelOrderLegs": []map[string]interface{}{{}},
+			},
+		},
+		"ContingentIdToCancel":   0,
+		"OrderIdToCancel":        0,
+		"OrderProcessingControl": 1, // Confirmation
+		"ConfirmCancelOrderId":   0,
+	}
+	extra := map[string]string{
+		"schwab-client-account":   accountID,
+		"schwab-resource-version": "2.0",
+	}
+
+	if err := c.UpdateTokenContext(ctx, "api"); err != nil && c.Debug {
+		log.Printf("UpdateToken(api) warning: %v", err)
+	}
+
+	confirm, err := c.postCancel(ctx, requestBody, extra)
+	if err != nil {
+		return result, err
+	}
+	if !confirm.CancelOrderConfirmation.IsCancelSuccess && confirm.CancelOrderConfirmation.OrderId == 0 {
+		return cancelOutcome(result, confirm), nil
+	}
+
+	requestBody["ConfirmCancelOrderId"] = confirm.CancelOrderConfirmation.OrderId
+	requestBody["OrderProcessingControl"] = 2 // Cancel
+
+	final, err := c.postCancel(ctx, requestBody, extra)
+	if err != nil {
+		return result, err
+	}
+	return cancelOutcome(result, final), nil
+}
+
+// CancelAllOpenOrders cancels every open order in the account, returning one result per order.
+// A failure on one order does not stop the others; all errors are joined in the returned error.
+func (c *Client) CancelAllOpenOrders(accountID string) ([]CancelResult, error) {
+	return c.CancelAllOpenOrdersContext(context.Background(), accountID)
+}
+
+// CancelAllOpenOrdersContext is like CancelAllOpenOrders but uses ctx for cancellation and deadlines.
+func (c *Client) CancelAllOpenOrdersContext(ctx context.Context, accountID string) ([]CancelResult, error) {
+	orders, err := c.GetOrdersContext(ctx, OrderFilter{AccountID: accountID})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CancelResult
+	var errs []error
+	seen := make(map[int64]bool)
+	for _, o := range orders {
+		if seen[o.OrderID] || !openOrderStatuses[strings.ToLower(o.Status)] {
+			continue
+		}
+		seen[o.OrderID] = true
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		res, err := c.CancelOrderOfTypeContext(ctx, accountID, o.OrderID, o.InstrumentType)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cancel order %d: %w", o.OrderID, err))
+			continue
+		}
+		results = append(results, res)
+	}
+	return results, errors.Join(errs...)
+}
+
+// postCancel sends a cancel payload and decodes the confirmation.
+func (c *Client) postCancel(ctx context.Context, requestBody map[string]interface{}, extra map[string]string) (CancelOrderV2Response, error) {
+	var resp CancelOrderV2Response
+	u := c.endpoint(CancelOrderV2Url)
+	status, body, err := c.do(ctx, "POST", u, requestBody, extra)
+	if err != nil {
+		return resp, err
+	}
+	if c.Debug {
+		log.Printf("Cancel Response: %s", string(body))
+	}
+	if status != 200 {
+		return resp, newAPIError("POST", u, status, body)
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// cancelOutcome classifies a cancel response into result.
+func cancelOutcome(result CancelResult, resp CancelOrderV2Response) CancelResult {
+	result.Messages = []string{}
+	filled := false
+	for _, msg := range resp.CancelOrderConfirmation.OrderMessages {
+		result.Messages = append(result.Messages, msg.Message)
+		if strings.Contains(strings.ToLower(msg.Message), "filled") {
+			filled = true
+		}
+	}
+	switch {
+	case resp.CancelOrderConfirmation.IsCancelSuccess:
+		result.Status = CancelStatusCancelled
+	case filled:
+		result.Status = CancelStatusAlreadyFilled
+	default:
+		result.Status = CancelStatusRejected
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package schwab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCancelOrder(t *testing.T) {
+	var steps []float64
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		step := body["OrderProcessingControl"].(float64)
+		steps = append(steps, step)
+		if step == 1 {
+			w.Write([]byte(`{"CancelOrderConfirmation":{"OrderId":555}}`))
+			return
+		}
+		if body["ConfirmCancelOrderId"].(float64) != 555 {
+			t.Errorf("ConfirmCancelOrderId = %v, want 555", body["ConfirmCancelOrderId"])
+		}
+		w.Write([]byte(`{"CancelOrderConfirmation":{"OrderId":555,"IsCancelSuccess":true,"OrderMessages":[{"message":"Cancel request received"}]}}`))
+	})
+
+	res, err := c.CancelOrder("123", 101)
+	if err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+	if res.Status != CancelStatusCancelled {
+		t.Errorf("Status = %s, want %s", res.Status, CancelStatusCancelled)
+	}
+	if len(res.Messages) != 1 || res.OrderID != 101 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if len(steps) != 2 || steps[0] != 1 || steps[1] != 2 {
+		t.Errorf("steps = %v, want [1 2]", steps)
+	}
+}
+
+func TestCancelOrder_AlreadyFilled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"CancelOrderConfirmation":{"OrderMessages":[{"message":"Order has already been filled"}]}}`))
+	})
+	res, err := c.CancelOrder("123", 101)
+	if err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+	if res.Status != CancelStatusAlreadyFilled {
+		t.Errorf("Status = %s, want %s", res.Status, CancelStatusAlreadyFilled)
+	}
+}
+
+func TestCancelAllOpenOrders(t *testing.T) {
+	var cancelled []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/orders/listView") {
+			w.Write([]byte(`{"Orders":[{"OrderList":[
+				{"OrderId":1,"DisplayStatus":"Open","OrderLegs":[{"Symbol":"AAPL"},{"Symbol":"AAPL"}]},
+				{"OrderId":2,"DisplayStatus":"Filled","OrderLegs":[{"Symbol":"MSFT"}]},
+				{"OrderId":3,"DisplayStatus":"Working","OrderLegs":[{"Symbol":"TSLA"}]},
+				{"OrderId":4,"DisplayStatus":"Open","OrderLegs":[{"Symbol":"SPY   240315P00490000"},{"Symbol":"SPY   240315C00510000"}]},
+				{"OrderId":5,"DisplayStatus":"Open","OrderLegs":[{"Symbol":"XYZ","SecurityType":48}]}
+			]}]}`))
+			return
+		}
+		var body struct {
+			Orders []struct {
+				OrderId        string
+				InstrumentType int
+			}
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		cancelled = append(cancelled, fmt.Sprintf("%s/%d", body.Orders[0].OrderId, body.Orders[0].InstrumentType))
+		w.Write([]byte(`{"CancelOrderConfirmation":{"OrderId":9,"IsCancelSuccess":true}}`))
+	})
+
+	results, err := c.CancelAllOpenOrders("123")
+	if err != nil {
+		t.Fatalf("CancelAllOpenOrders: %v", err)
+	}
+	if len(results) != 4 || results[0].OrderID != 1 || results[1].OrderID != 3 || results[2].OrderID != 4 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	// Two requests (confirm + cancel) per open order, each with the order's instrument type.
+	if strings.Join(cancelled, ",") != "1/46,1/46,3/46,3/46,4/48,4/48,5/48,5/48" {
+		t.Errorf("cancel requests = %v", cancelled)
+	}
+}
@@ -0,0 +1,188 @@
+package schwab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// LoginState is a step of the login flow reported to LoginOptions.OnLoginState.
+type LoginState string
+
+const (
+	// LoginStateCredentialsSubmitted means the username and password were entered.
+	LoginStateCredentialsSubmitted LoginState = "credentials_submitted"
+	// LoginStateSecurityCodeRequired means Schwab asked for a one-time code (SMS or security code).
+	LoginStateSecurityCodeRequired LoginState = "security_code_required"
+	// LoginStateAwaitingPushApproval means Schwab sent a push notification (e.g. Symantec VIP)
+	// and the login is waiting for it to be approved on the device.
+	LoginStateAwaitingPushApproval LoginState = "awaiting_push_approval"
+	// LoginStateVerified means an extra verification step was completed.
+	LoginStateVerified LoginState = "verified"
+)
+
+var (
+	// ErrSecurityCodeRequired is returned when Schwab asks for a security code and LoginOptions.OnSecurityCode is nil.
+	ErrSecurityCodeRequired = errors.New("schwab: security code required but no OnSecurityCode callback is set")
+	// ErrPushApprovalTimeout is returned when a push notification is not approved within LoginOptions.ChallengeTimeout.
+	ErrPushApprovalTimeout = errors.New("schwab: push notification was not approved in time")
+)
+
+// challenge is the kind of extra verification screen shown after the credentials.
+type challenge int
+
+const (
+	challengeNone challenge = iota
+	challengeSecurityCode
+	challengePush
+)
+
+// Selectors for the verification screens. Schwab's UI is inconsistent, so several
+// variants are tried (same ones as Python schwab-api).
+const (
+	securityCodeInputSel = `input[name="otpcode"], input#otpcode, input[autocomplete="one-time-code"]`
+	smsOptionSel         = `[aria-label="Text me a 6 digit security code"]`
+	deliveryMethodSel    = `input[name="DeliveryMethodSelection"]`
+	pushPromptSel        = `text=/approve.*(notification|request|sign.?in)|push notification|VIP Access/i`
+	trustDeviceSel       = `input#TrustDeviceChecked`
+)
+
+var tradeURLRe = regexp.MustCompile(`app/trade`)
+
+// detectChallenge watches for a verification screen for up to window and returns
+// challengeNone if none appears.
+func detectChallenge(ctx context.Context, page playwright.Page, fl playwright.FrameLocator, window time.Duration) (challenge, error) {
+	deadline := time.Now().Add(window)
+	for {
+		if findChallengeElement(page, fl, securityCodeInputSel, smsOptionSel, deliveryMethodSel) != nil {
+			return challengeSecurityCode, nil
+		}
+		if findChallengeElement(page, fl, pushPromptSel) != nil {
+			return challengePush, nil
+		}
+		if !time.Now().Before(deadline) {
+			return challengeNone, nil
+		}
+		if err := sleepContext(ctx, 250*time.Millisecond); err != nil {
+			return challengeNone, err
+		}
+	}
+}
+
+// findChallengeElement returns the first visible match for any selector, looking in the
+// login iframe first and then the top-level page, or nil if there is none.
+func findChallengeElement(page playwright.Page, fl playwright.FrameLocator, selectors ...string) playwright.Locator {
+	for _, sel := range selectors {
+		for _, l := range []playwright.Locator{fl.Locator(sel).First(), page.Locator(sel).First()} {
+			if ok, err := l.IsVisible(); err == nil && ok {
+				return l
+			}
+		}
+	}
+	return nil
+}
+
+// waitForChallengeElement polls findChallengeElement until a match appears or timeout elapses.
+func waitForChallengeElement(ctx context.Context, page playwright.Page, fl playwright.FrameLocator, timeout time.Duration, selectors ...string) (playwright.Locator, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		if l := findChallengeElement(page, fl, selectors...); l != nil {
+			return l, nil
+		}
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s", selectors[0])
+		}
+		if err := sleepContext(ctx, 250*time.Millisecond); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// handleChallenge completes a verification screen found by detectChallenge.
+func (a *PlaywrightAuthenticator) handleChallenge(ctx context.Context, page playwright.Page, fl playwright.FrameLocator, opts LoginOptions, ch challenge) error {
+	switch ch {
+	case challengeSecurityCode:
+		if err := a.enterSecurityCode(ctx, page, fl, opts); err != nil {
+			return err
+		}
+	case challengePush:
+		a.report(opts, LoginStateAwaitingPushApproval)
+		err := page.WaitForURL(tradeURLRe, playwright.PageWaitForURLOptions{Timeout: millis(opts.ChallengeTimeout)})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("%w: %v", ErrPushApprovalTimeout, err)
+		}
+	default:
+		return nil
+	}
+	a.report(opts, LoginStateVerified)
+	return nil
+}
+
+// enterSecurityCode requests a code by text message if needed, asks OnSecurityCode for it and submits it.
+func (a *PlaywrightAuthenticator) enterSecurityCode(ctx context.Context, page playwright.Page, fl playwright.FrameLocator, opts LoginOptions) error {
+	a.report(opts, LoginStateSecurityCodeRequired)
+	if opts.OnSecurityCode == nil {
+		return ErrSecurityCodeRequired
+	}
+
+	// Schwab may first ask how to deliver the code; choose text message.
+	if l := findChallengeElement(page, fl, smsOptionSel); l != nil {
+		if err := l.Click(); err != nil {
+			return fmt.Errorf("failed to request security code: %w", err)
+		}
+	} else if l := findChallengeElement(page, fl, deliveryMethodSel); l != nil {
+		if err := l.Click(); err != nil {
+			return fmt.Errorf("failed to choose delivery method: %w", err)
+		}
+		if l := findChallengeElement(page, fl, "text=Text Message"); l != nil {
+			_ = l.Click()
+		}
+		if l := findChallengeElement(page, fl, `input:has-text("Continue")`, `button:has-text("Continue")`); l != nil {
+			if err := l.Click(); err != nil {
+				return fmt.Errorf("failed to request security code: %w", err)
+			}
+		}
+	}
+
+	input, err := waitForChallengeElement(ctx, page, fl, opts.SelectorTimeout, securityCodeInputSel)
+	if err != nil {
+		return fmt.Errorf("failed to find security code field: %w", err)
+	}
+	code, err := opts.OnSecurityCode(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get security code: %w", err)
+	}
+	if err := input.Fill(code); err != nil {
+		return fmt.Errorf("failed to fill security code: %w", err)
+	}
+	if l := findChallengeElement(page, fl, trustDeviceSel); l != nil {
+		_ = l.Check()
+	}
+	if err := input.Press("Enter"); err != nil {
+		return fmt.Errorf("failed to submit security code: %w", err)
+	}
+
+	err = page.WaitForURL(tradeURLRe, playwright.PageWaitForURLOptions{Timeout: millis(opts.ChallengeTimeout)})
+	if err != nil {
+		return fmt.Errorf("security code was not accepted: %w", err)
+	}
+	return nil
+}
+
+// report passes state to OnLoginState and logs it in debug mode.
+func (a *PlaywrightAuthenticator) report(opts LoginOptions, state LoginState) {
+	if a.Debug {
+		log.Printf("Login state: %s", state)
+	}
+	if opts.OnLoginState != nil {
+		opts.OnLoginState(state)
+	}
+}
@@ -0,0 +1,33 @@
+package schwab
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEnterSecurityCode_NoCallback(t *testing.T) {
+	var states []LoginState
+	a := &PlaywrightAuthenticator{}
+	opts := LoginOptions{OnLoginState: func(s LoginState) { states = append(states, s) }}.withDefaults()
+
+	// No callback: fail before touching the page.
+	err := a.enterSecurityCode(context.Background(), nil, nil, opts)
+	if !errors.Is(err, ErrSecurityCodeRequired) {
+		t.Fatalf("err = %v, want ErrSecurityCodeRequired", err)
+	}
+	if len(states) != 1 || states[0] != LoginStateSecurityCodeRequired {
+		t.Errorf("states = %v, want [%s]", states, LoginStateSecurityCodeRequired)
+	}
+}
+
+func TestHandleChallenge_None(t *testing.T) {
+	called := false
+	opts := LoginOptions{OnLoginState: func(LoginState) { called = true }}.withDefaults()
+	if err := (&PlaywrightAuthenticator{}).handleChallenge(context.Background(), nil, nil, opts, challengeNone); err != nil {
+		t.Fatalf("handleChallenge: %v", err)
+	}
+	if called {
+		t.Error("OnLoginState called without a challenge")
+	}
+}
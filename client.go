@@ -2,17 +2,43 @@ package schwab
 
 import (
 	"net/http"
+	"sync"
+	"time"
 )
 
-// Client represents the Schwab API client
+// Client represents the Schwab API client.
+// A Client is safe for concurrent use. Configure the exported fields before making calls;
+// after that, replace the session with RestoreSession or Authenticate rather than writing
+// Headers or BearerToken directly.
 type Client struct {
 	HttpClient  *http.Client
 	Headers     map[string]string
 	BearerToken string
 	Debug       bool
 	// AccountIDs optionally specifies account number(s) for API calls that require Schwab-Client-Ids (e.g. HoldingV2).
-	// If set, GetAccountInfo will send the first ID in the Schwab-Client-Ids header.
+	// If set, GetAccountInfo fetches holdings for each ID in turn.
 	AccountIDs []string
+	// DiscoverAccounts makes Login fill AccountIDs from ListAccounts when none are configured.
+	DiscoverAccounts bool
+	// Endpoints overrides the Schwab hosts, e.g. to use a proxy or a local test server.
+	Endpoints Endpoints
+	// LoginOptions configures the browser used by Login; zero values use DefaultLoginOptions.
+	LoginOptions LoginOptions
+	// AckPolicy, if set, decides which order verification warnings to acknowledge before execution.
+	// Either way, orders proceed only when the verification return code is 0 or 10 and no message is an error.
+	AckPolicy *AckPolicy
+	// Reauth, if set, re-authenticates and retries once when the session is rejected.
+	Reauth *ReauthPolicy
+
+	// mu guards Headers, BearerToken, AccountIDs and the session fields below.
+	mu sync.RWMutex
+	// capturedAt is when Login captured the current session.
+	capturedAt time.Time
+	// sessionGen is incremented whenever the session is replaced, so requests that failed
+	// with the same session trigger only one re-authentication.
+	sessionGen uint64
+	// reauthMu serializes re-authentication.
+	reauthMu sync.Mutex
 }
 
 // NewClient creates a new Schwab API client
@@ -23,3 +49,69 @@ func NewClient(debug bool) *Client {
 		Debug:      debug,
 	}
 }
+
+// requestHeaders returns a copy of the session headers for building one request.
+func (c *Client) requestHeaders() map[string]string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	headers := make(map[string]string, len(c.Headers))
+	for k, v := range c.Headers {
+		headers[k] = v
+	}
+	return headers
+}
+
+// header returns one session header.
+func (c *Client) header(name string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.Headers[name]
+	return v, ok
+}
+
+// isSessionHeader reports whether name is Authorization or Cookie in any casing.
+// The browser capture uses lowercase keys, and a second copy under another casing
+// would be sent in random order with the client's own value.
+func isSessionHeader(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Cookie":
+		return true
+	}
+	return false
+}
+
+// setBearerToken replaces the bearer token. Headers is copied rather than modified in place.
+func (c *Client) setBearerToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	headers := make(map[string]string, len(c.Headers)+1)
+	for k, v := range c.Headers {
+		if http.CanonicalHeaderKey(k) != "Authorization" {
+			headers[k] = v
+		}
+	}
+	headers["Authorization"] = token
+	c.Headers = headers
+	c.BearerToken = token
+}
+
+// accountIDs returns a copy of AccountIDs.
+func (c *Client) accountIDs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.AccountIDs...)
+}
+
+// setAccountIDs replaces AccountIDs.
+func (c *Client) setAccountIDs(ids []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.AccountIDs = ids
+}
+
+// generation returns the current session generation.
+func (c *Client) generation() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sessionGen
+}
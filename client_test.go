@@ -1,9 +1,39 @@
 package schwab
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 )
 
+// newTestClient returns a Client whose Endpoints all point at a local server running handler.
+// Token refreshes are answered automatically with token "test-token".
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	return newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/auth/authorize/scope/") {
+			w.Write([]byte(`{"token":"test-token"}`))
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// newTestServerClient is newTestClient without the automatic token refresh responses.
+func newTestServerClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c := NewClient(false)
+	c.Endpoints = Endpoints{WWW: srv.URL, Client: srv.URL, AusGateway: srv.URL}
+	return c
+}
+
 func TestNewClient(t *testing.T) {
 	// debug off
 	c := NewClient(false)
@@ -32,3 +62,86 @@ func TestNewClient(t *testing.T) {
 		t.Error("Debug should be true")
 	}
 }
+
+// TestClient_ConcurrentUse exercises one Client from many goroutines; run with -race.
+func TestClient_ConcurrentUse(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != "c=1" {
+			t.Errorf("%s: Cookie = %q", r.URL.Path, r.Header.Get("Cookie"))
+		}
+		if strings.HasSuffix(r.URL.Path, "/HoldingV2") {
+			id := r.Header.Get("Schwab-Client-Ids")
+			w.Write([]byte(`{"accounts":[{"accountId":"` + id + `"}]}`))
+			return
+		}
+		w.Write([]byte(`{"quotes":[{"symbol":"AAPL","quote":{"last":"1"}}]}`))
+	})
+	c.AccountIDs = []string{"111", "222"}
+	c.RestoreSession(Session{BearerToken: "Bearer start", Cookies: "c=1"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			if accounts, err := c.GetAccountInfo(); err != nil || len(accounts) != 2 {
+				t.Errorf("GetAccountInfo = %d accounts, %v", len(accounts), err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetQuotes("AAPL"); err != nil {
+				t.Errorf("GetQuotes: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := c.UpdateToken("api"); err != nil {
+				t.Errorf("UpdateToken: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			s := c.Session()
+			s.CapturedAt = s.CapturedAt.Add(1)
+			c.RestoreSession(s)
+		}()
+	}
+	wg.Wait()
+
+	if _, ok := c.Session().Headers["Schwab-Client-Ids"]; ok {
+		t.Error("per-account header leaked into the session")
+	}
+}
+
+// TestReauth_ConcurrentSingleLogin checks that requests rejected together share one re-authentication.
+func TestReauth_ConcurrentSingleLogin(t *testing.T) {
+	var rejected atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != "fresh" {
+			rejected.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"quotes":[]}`))
+	})
+	c.RestoreSession(Session{BearerToken: "Bearer stale", Cookies: "stale"})
+	auth := &staticAuthenticator{session: Session{BearerToken: "Bearer fresh", Cookies: "fresh"}}
+	c.Reauth = &ReauthPolicy{Authenticator: auth}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetQuotesContext(context.Background(), "AAPL"); err != nil {
+				t.Errorf("GetQuotes: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if auth.calls != 1 {
+		t.Errorf("authenticator called %d times after %d rejections, want 1", auth.calls, rejected.Load())
+	}
+}
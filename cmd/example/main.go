@@ -1,6 +1,8 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -50,8 +52,27 @@ func main() {
 	client := schwab.NewClient(true) // Enable debug
 	if accountNumbers := os.Getenv("SCHWAB_ACCOUNT_NUMBERS"); accountNumbers != "" {
 		client.AccountIDs = strings.Split(strings.TrimSpace(accountNumbers), ":")
+	} else {
+		// Fill AccountIDs from the accounts endpoint after login
+		client.DiscoverAccounts = true
+	}
+	// Accounts without TOTP may get an SMS code or a push notification instead
+	client.LoginOptions.OnSecurityCode = func(ctx context.Context) (string, error) {
+		fmt.Print("Enter the security code Schwab sent you: ")
+		code, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		return strings.TrimSpace(code), err
+	}
+	client.LoginOptions.OnLoginState = func(state schwab.LoginState) {
+		if state == schwab.LoginStateAwaitingPushApproval {
+			fmt.Println("Approve the login notification on your phone...")
+		}
+	}
+	var err error
+	if sessionFile := os.Getenv("SCHWAB_SESSION_FILE"); sessionFile != "" {
+		err = client.LoginOrRestore(sessionFile, username, password, totpSecret)
+	} else {
+		err = client.Login(username, password, totpSecret)
 	}
-	err := client.Login(username, password, totpSecret)
 	if err != nil {
 		log.Fatalf("Login failed: %v", err)
 	}
@@ -62,7 +83,10 @@ func main() {
 	fmt.Println("Fetching Account Info...")
 	accountsMap, err := client.GetAccountInfo()
 	if err != nil {
-		log.Fatalf("Failed to get account info: %v", err)
+		if len(accountsMap) == 0 {
+			log.Fatalf("Failed to get account info: %v", err)
+		}
+		log.Printf("Some accounts failed: %v", err)
 	}
 
 	for id, acc := range accountsMap {
@@ -0,0 +1,164 @@
+package schwab
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Causes of a failed login recognized from the page Schwab showed. A *LoginError wraps
+// at most one of them, so they can be tested with errors.Is.
+var (
+	ErrInvalidCredentials = errors.New("schwab: invalid login ID or password")
+	ErrAccountLocked      = errors.New("schwab: account locked")
+	ErrSiteMaintenance    = errors.New("schwab: site unavailable for maintenance")
+	ErrCaptcha            = errors.New("schwab: captcha required")
+)
+
+// LoginError is returned when the browser login fails after the page has opened.
+type LoginError struct {
+	// Reason is ErrInvalidCredentials, ErrAccountLocked, ErrSiteMaintenance, ErrCaptcha or nil if not recognized.
+	Reason error
+	// URL is the page URL at the time of the failure.
+	URL string
+	// DiagnosticsDir is the directory the failure diagnostics were saved in, if any.
+	DiagnosticsDir string
+	// Err is the error from the failing step.
+	Err error
+}
+
+func (e *LoginError) Error() string {
+	msg := e.Err.Error()
+	if e.Reason != nil {
+		msg = e.Reason.Error() + ": " + msg
+	}
+	if e.DiagnosticsDir != "" {
+		msg += " (diagnostics saved in " + e.DiagnosticsDir + ")"
+	}
+	return msg
+}
+
+// Unwrap exposes Reason and Err to errors.Is and errors.As.
+func (e *LoginError) Unwrap() []error {
+	if e.Reason == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Reason, e.Err}
+}
+
+// loginFailurePatterns maps the text of the login form and alerts to a failure cause, checked in order.
+// They match whole phrases, since the form and alerts can also carry unrelated notices
+// (e.g. an announcement of a maintenance window while the real problem is a wrong password).
+var loginFailurePatterns = []struct {
+	re     *regexp.Regexp
+	reason error
+}{
+	{regexp.MustCompile(`(?i)complete the (captcha|security check)|verify (that )?you('| a)re (a )?human|are you a robot`), ErrCaptcha},
+	{regexp.MustCompile(`(?i)account (has been |is )?(locked|disabled|suspended)|too many (failed|unsuccessful) (login )?attempts`), ErrAccountLocked},
+	{regexp.MustCompile(`(?i)(is|are) (currently |temporarily )?(unavailable|down) (due to|for|during) (scheduled |system )?maintenance|(is|are) (currently )?undergoing (scheduled |system )?maintenance`), ErrSiteMaintenance},
+	{regexp.MustCompile(`(?i)(incorrect|invalid|not recognized|could ?n[o'’]t verify).{0,60}(login id|password|credentials)|(login id|password).{0,60}(incorrect|invalid|not recognized|do(es)? not match)`), ErrInvalidCredentials},
+}
+
+// classifyLoginFailure returns the failure cause described by the page text, or nil.
+func classifyLoginFailure(text string) error {
+	for _, p := range loginFailurePatterns {
+		if p.re.MatchString(text) {
+			return p.reason
+		}
+	}
+	return nil
+}
+
+// loginFailure wraps err in a *LoginError, classifying the page and saving diagnostics when configured.
+func (a *PlaywrightAuthenticator) loginFailure(page playwright.Page, browserCtx playwright.BrowserContext, opts LoginOptions, err error) error {
+	le := &LoginError{URL: page.URL(), Err: err}
+	le.Reason = classifyLoginFailure(failureText(page))
+	if opts.DiagnosticsDir != "" {
+		dir, derr := saveDiagnostics(page, browserCtx, opts.DiagnosticsDir)
+		if derr != nil && a.Debug {
+			log.Printf("Saving login diagnostics failed: %v", derr)
+		}
+		le.DiagnosticsDir = dir
+	}
+	return le
+}
+
+// loginAlertSelector matches the error and alert elements the login page uses for messages.
+const loginAlertSelector = `[role="alert"], [aria-live="assertive"], .alert, .error, .errorMessage, .error-message`
+
+// failureText returns the text of the login iframe and of the visible alerts on the page.
+// The rest of the page (marketing copy, footers, scripts) is left out so it cannot be misread as the cause,
+// except when there is no login iframe: then the page itself is the notice (e.g. a maintenance page)
+// and its whole body text is used.
+func failureText(page playwright.Page) string {
+	timeout := playwright.Float(2000)
+	var parts []string
+	main := page.Locator("body")
+	if n, err := page.Locator("iframe#schwablmslogin").Count(); err == nil && n > 0 {
+		main = page.FrameLocator("iframe#schwablmslogin").Locator("body")
+	}
+	if text, err := main.InnerText(playwright.LocatorInnerTextOptions{Timeout: timeout}); err == nil {
+		parts = append(parts, text)
+	}
+	alerts, err := page.Locator(loginAlertSelector).All()
+	if err != nil {
+		return strings.Join(parts, "\n")
+	}
+	for _, l := range alerts {
+		if ok, err := l.IsVisible(); err != nil || !ok {
+			continue
+		}
+		if text, err := l.InnerText(playwright.LocatorInnerTextOptions{Timeout: timeout}); err == nil {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// saveDiagnostics writes a screenshot, the HTML of every frame, the URLs and the Playwright trace
+// to a new timestamped directory under dir and returns its path. It keeps going after individual
+// failures and returns them joined.
+func saveDiagnostics(page playwright.Page, browserCtx playwright.BrowserContext, dir string) (string, error) {
+	out := filepath.Join(dir, "login-"+time.Now().Format("20060102-150405.000"))
+	if err := os.MkdirAll(out, 0700); err != nil {
+		return "", err
+	}
+
+	var errs []error
+	if png, err := page.Screenshot(playwright.PageScreenshotOptions{FullPage: playwright.Bool(true)}); err != nil {
+		errs = append(errs, fmt.Errorf("screenshot: %w", err))
+	} else {
+		errs = append(errs, writePrivateFile(filepath.Join(out, "screenshot.png"), png))
+	}
+
+	var urls strings.Builder
+	for i, f := range page.Frames() {
+		name := "page.html"
+		if i > 0 {
+			name = fmt.Sprintf("frame-%d.html", i)
+		}
+		fmt.Fprintf(&urls, "%s\t%s\n", name, f.URL())
+		html, err := f.Content()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		errs = append(errs, writePrivateFile(filepath.Join(out, name), []byte(html)))
+	}
+	errs = append(errs, writePrivateFile(filepath.Join(out, "url.txt"), []byte(urls.String())))
+
+	trace := filepath.Join(out, "trace.zip")
+	if err := browserCtx.Tracing().Stop(trace); err != nil {
+		errs = append(errs, fmt.Errorf("trace: %w", err))
+	} else {
+		errs = append(errs, os.Chmod(trace, 0600))
+	}
+	return out, errors.Join(errs...)
+}
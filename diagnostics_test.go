@@ -0,0 +1,78 @@
+package schwab
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClassifyLoginFailure(t *testing.T) {
+	tests := []struct {
+		text string
+		want error
+	}{
+		{"We're sorry, the Login ID or password you entered is incorrect.", ErrInvalidCredentials},
+		{"We couldn't verify your login ID and password.", ErrInvalidCredentials},
+		{"Your account has been locked after too many failed attempts.", ErrAccountLocked},
+		{"Too many unsuccessful login attempts", ErrAccountLocked},
+		{"Schwab.com is undergoing scheduled maintenance. Please try again later.", ErrSiteMaintenance},
+		{"Login is currently unavailable due to maintenance.", ErrSiteMaintenance},
+		{"Please complete the CAPTCHA below", ErrCaptcha},
+		{"Verify you are human", ErrCaptcha},
+		// Notices that only mention maintenance or captcha must not hide the real cause.
+		{"Scheduled maintenance: some services may be unavailable Saturday 10pm-2am ET.\nThe Login ID or password you entered is incorrect.", ErrInvalidCredentials},
+		{"Planned system maintenance this weekend\nLog in\nLogin ID\nPassword", nil},
+		{"This service is temporarily unavailable", nil},
+		{"Protected by reCAPTCHA", nil},
+		{"Log in\nLogin ID\nPassword\nForgot Login ID or Password?", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := classifyLoginFailure(tt.text); got != tt.want {
+			t.Errorf("classifyLoginFailure(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyLoginFailure_MaintenancePage(t *testing.T) {
+	// Without the login iframe the whole page body is classified, navigation and footer included.
+	body := `Charles Schwab
+Log In
+Schwab.com is currently unavailable due to scheduled maintenance.
+We expect to be back by 6:00 a.m. ET. We apologize for any inconvenience.
+Contact Us | Privacy | Security Center
+Brokerage products: Not FDIC insured`
+	if got := classifyLoginFailure(body); got != ErrSiteMaintenance {
+		t.Errorf("classifyLoginFailure(maintenance page) = %v, want ErrSiteMaintenance", got)
+	}
+
+	homepage := `Charles Schwab
+Log In
+Planned maintenance: some features may be unavailable this weekend.
+Open an account
+Contact Us | Privacy | Security Center`
+	if got := classifyLoginFailure(homepage); got != nil {
+		t.Errorf("classifyLoginFailure(homepage) = %v, want nil", got)
+	}
+}
+
+func TestLoginError(t *testing.T) {
+	stepErr := errors.New("failed to wait for login iframe: timeout")
+	err := error(&LoginError{Reason: ErrSiteMaintenance, URL: "https://www.schwab.com/", DiagnosticsDir: "diag/login-1", Err: stepErr})
+	if !errors.Is(err, ErrSiteMaintenance) || !errors.Is(err, stepErr) {
+		t.Errorf("errors.Is failed for %v", err)
+	}
+	var le *LoginError
+	if !errors.As(err, &le) || le.URL != "https://www.schwab.com/" {
+		t.Errorf("errors.As = %+v", le)
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, ErrSiteMaintenance.Error()) || !strings.Contains(msg, "diag/login-1") {
+		t.Errorf("Error() = %q", msg)
+	}
+
+	plain := &LoginError{Err: stepErr}
+	if plain.Error() != stepErr.Error() || errors.Is(plain, ErrInvalidCredentials) {
+		t.Errorf("unclassified error = %q", plain.Error())
+	}
+}
@@ -1,5 +1,8 @@
 package schwab
 
+import "strings"
+
+// Every URL below is on one of the hosts in DefaultEndpoints; Client.Endpoints can point them elsewhere.
 const (
 	HomepageUrl            = "https://www.schwab.com/"
 	AccountSummaryUrl      = "https://client.schwab.com/clientapps/accounts/summary/"
@@ -13,9 +16,54 @@ const (
 	TransactionHistoryV2Url = "https://ausgateway.schwab.com/api/is.TransactionHistoryWeb/TransactionHistoryInterface/TransactionHistory/brokerage/transactions/export"
 	LotDetailsV2Url        = "https://ausgateway.schwab.com/api/is.Holdings/V1/Lots"
 	OptionChainsV2Url      = "https://ausgateway.schwab.com/api/is.CSOptionChainsWeb/v1/OptionChainsPort/OptionChains/chains"
+	AuthorizeScopeUrl      = "https://client.schwab.com/api/auth/authorize/scope/"
 
 	// Old API
 	PositionsDataUrl       = "https://client.schwab.com/api/PositionV2/PositionsDataV2"
 	OrderVerificationUrl   = "https://client.schwab.com/api/ts/stamp/verifyOrder"
 	OrderConfirmationUrl   = "https://client.schwab.com/api/ts/stamp/confirmorder"
 )
+
+// Endpoints holds the Schwab hosts the client talks to, each as a scheme and host without a
+// trailing slash (optionally with a path prefix), e.g. "http://127.0.0.1:8080". Empty fields
+// use the values from DefaultEndpoints.
+type Endpoints struct {
+	WWW        string
+	Client     string
+	AusGateway string
+}
+
+// DefaultEndpoints returns the production Schwab hosts.
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		WWW:        "https://www.schwab.com",
+		Client:     "https://client.schwab.com",
+		AusGateway: "https://ausgateway.schwab.com",
+	}
+}
+
+// resolve rewrites one of the URL constants to the configured host.
+func (e Endpoints) resolve(u string) string {
+	d := DefaultEndpoints()
+	for _, h := range []struct{ def, override string }{
+		{d.WWW, e.WWW},
+		{d.Client, e.Client},
+		{d.AusGateway, e.AusGateway},
+	} {
+		if h.override != "" && strings.HasPrefix(u, h.def) {
+			return strings.TrimSuffix(h.override, "/") + strings.TrimPrefix(u, h.def)
+		}
+	}
+	return u
+}
+
+// cookieURLs are the hosts whose cookies make up the session.
+func (e Endpoints) cookieURLs() []string {
+	d := DefaultEndpoints()
+	return []string{e.resolve(d.WWW), e.resolve(d.Client), e.resolve(d.AusGateway)}
+}
+
+// endpoint resolves a URL constant against c.Endpoints.
+func (c *Client) endpoint(u string) string {
+	return c.Endpoints.resolve(u)
+}
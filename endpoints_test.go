@@ -1,6 +1,8 @@
 package schwab
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -21,3 +23,51 @@ func TestEndpoints_NonEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestEndpoints_Resolve(t *testing.T) {
+	var zero Endpoints
+	if got := zero.resolve(PositionsV2Url); got != PositionsV2Url {
+		t.Errorf("zero Endpoints changed %s to %s", PositionsV2Url, got)
+	}
+
+	e := Endpoints{WWW: "http://www.local/", Client: "http://client.local", AusGateway: "http://gw.local/prefix"}
+	tests := map[string]string{
+		HomepageUrl:               "http://www.local/",
+		AuthorizeScopeUrl + "api": "http://client.local/api/auth/authorize/scope/api",
+		PositionsV2Url:            "http://gw.local/prefix/api/is.Holdings/V1/Holdings/HoldingV2",
+		"https://example.com/x":   "https://example.com/x",
+	}
+	for in, want := range tests {
+		if got := e.resolve(in); got != want {
+			t.Errorf("resolve(%s) = %s, want %s", in, got, want)
+		}
+	}
+
+	urls := e.cookieURLs()
+	if len(urls) != 3 || urls[0] != "http://www.local" || urls[2] != "http://gw.local/prefix" {
+		t.Errorf("cookieURLs() = %v", urls)
+	}
+}
+
+func TestEndpoints_PerHost(t *testing.T) {
+	var tokenHits, gatewayHits int
+	client := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenHits++
+		w.Write([]byte(`{"token":"t"}`))
+	}))
+	defer client.Close()
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gatewayHits++
+		w.Write([]byte(`{"accounts":[{"accountId":"1"}]}`))
+	}))
+	defer gateway.Close()
+
+	c := NewClient(false)
+	c.Endpoints = Endpoints{Client: client.URL, AusGateway: gateway.URL}
+	if _, err := c.GetAccountInfo(); err != nil {
+		t.Fatalf("GetAccountInfo: %v", err)
+	}
+	if tokenHits != 1 || gatewayHits != 1 {
+		t.Errorf("token server hits = %d, gateway hits = %d; want 1 each", tokenHits, gatewayHits)
+	}
+}
@@ -0,0 +1,152 @@
+package schwab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors matched by *APIError with errors.Is.
+var (
+	// ErrUnauthorized means Schwab rejected the session (HTTP 401 or 403).
+	ErrUnauthorized = errors.New("schwab: unauthorized")
+	// ErrRateLimited means Schwab throttled the request (HTTP 429).
+	ErrRateLimited = errors.New("schwab: rate limited")
+	// ErrOrderRejected means an order failed verification or execution.
+	ErrOrderRejected = errors.New("schwab: order rejected")
+	// ErrSessionExpired means the session can no longer be used and a new login is needed:
+	// a 401, a rejected token refresh, or a response saying the session expired.
+	ErrSessionExpired = errors.New("schwab: session expired")
+)
+
+// APIError is returned when a Schwab endpoint answers with an error.
+type APIError struct {
+	// Endpoint is the request method and URL without the query, e.g. "GET https://.../HoldingV2".
+	Endpoint string
+	// StatusCode is the HTTP status (200 for an order rejected in a successful response).
+	StatusCode int
+	// Code is Schwab's error code from the response body, or the order return code for a rejected order.
+	Code string
+	// Message is Schwab's error message, or the truncated body when there is none.
+	Message string
+
+	body []byte
+	// kind is a sentinel implied by the context rather than the status, e.g. ErrOrderRejected.
+	kind error
+}
+
+// newAPIError builds an *APIError from an HTTP response, extracting Schwab's code and message from body.
+func newAPIError(method, url string, status int, body []byte) *APIError {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		url = url[:i]
+	}
+	e := &APIError{Endpoint: method + " " + url, StatusCode: status, body: body}
+	e.Code, e.Message = parseErrorBody(body)
+	if e.Message == "" {
+		e.Message = truncate(strings.TrimSpace(string(body)))
+	}
+	if sessionExpiredRe.MatchString(e.Message) {
+		e.kind = ErrSessionExpired
+	}
+	return e
+}
+
+// newOrderRejectedError reports an order whose response carried a failing return code.
+func newOrderRejectedError(url string, returnCode int, messages []string, body []byte) *APIError {
+	return &APIError{
+		Endpoint:   "POST " + url,
+		StatusCode: http.StatusOK,
+		Code:       strconv.Itoa(returnCode),
+		Message:    strings.Join(messages, "; "),
+		body:       body,
+		kind:       ErrOrderRejected,
+	}
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("schwab: %s failed with status %d", e.Endpoint, e.StatusCode)
+	if e.kind == ErrOrderRejected {
+		msg = fmt.Sprintf("schwab: %s rejected the order", e.Endpoint)
+	}
+	if e.Code != "" {
+		msg += " (code " + e.Code + ")"
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Body returns the raw response body.
+func (e *APIError) Body() []byte {
+	return e.body
+}
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return needsReauth(e.StatusCode)
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrSessionExpired:
+		return e.StatusCode == http.StatusUnauthorized || e.kind == ErrSessionExpired
+	}
+	return target != nil && target == e.kind
+}
+
+var sessionExpiredRe = regexp.MustCompile(`(?i)session.{0,30}(expired|timed out)|token.{0,20}expired`)
+
+// parseErrorBody extracts an error code and message from the JSON error shapes Schwab uses.
+// Field names are matched case-insensitively.
+func parseErrorBody(body []byte) (code, message string) {
+	var v struct {
+		ErrorCode    json.RawMessage `json:"errorCode"`
+		Code         json.RawMessage `json:"code"`
+		ErrorMessage string          `json:"errorMessage"`
+		Message      string          `json:"message"`
+		Detail       string          `json:"detail"`
+		Title        string          `json:"title"`
+		Errors       []struct {
+			Code    json.RawMessage `json:"code"`
+			Message string          `json:"message"`
+			Detail  string          `json:"detail"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return "", ""
+	}
+	code = firstNonEmpty(rawString(v.ErrorCode), rawString(v.Code))
+	message = firstNonEmpty(v.ErrorMessage, v.Message, v.Detail, v.Title)
+	if len(v.Errors) > 0 {
+		code = firstNonEmpty(code, rawString(v.Errors[0].Code))
+		message = firstNonEmpty(message, v.Errors[0].Message, v.Errors[0].Detail)
+	}
+	return code, message
+}
+
+// rawString renders a JSON string or number as text.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,114 @@
+package schwab
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseErrorBody(t *testing.T) {
+	tests := []struct {
+		body, code, message string
+	}{
+		{`{"errorCode":"E123","errorMessage":"Bad account"}`, "E123", "Bad account"},
+		{`{"Code":42,"Message":"Nope"}`, "42", "Nope"},
+		{`{"title":"Forbidden","detail":"Session expired"}`, "", "Session expired"},
+		{`{"errors":[{"code":"X1","message":"first"},{"code":"X2"}]}`, "X1", "first"},
+		{`<html>gateway timeout</html>`, "", ""},
+	}
+	for _, tt := range tests {
+		code, msg := parseErrorBody([]byte(tt.body))
+		if code != tt.code || msg != tt.message {
+			t.Errorf("parseErrorBody(%s) = %q, %q, want %q, %q", tt.body, code, msg, tt.code, tt.message)
+		}
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	tests := []struct {
+		err  *APIError
+		want []error
+	}{
+		{newAPIError("GET", "https://x/a?b=1", 401, nil), []error{ErrUnauthorized, ErrSessionExpired}},
+		{newAPIError("GET", "https://x/a", 403, nil), []error{ErrUnauthorized}},
+		{newAPIError("GET", "https://x/a", 429, nil), []error{ErrRateLimited}},
+		{newAPIError("GET", "https://x/a", 400, []byte(`{"message":"Your session has expired"}`)), []error{ErrSessionExpired}},
+		{newOrderRejectedError("https://x/order", 20, []string{"Insufficient funds"}, nil), []error{ErrOrderRejected}},
+		{newAPIError("GET", "https://x/a", 500, []byte("oops")), nil},
+	}
+	all := []error{ErrUnauthorized, ErrRateLimited, ErrOrderRejected, ErrSessionExpired}
+	for _, tt := range tests {
+		for _, sentinel := range all {
+			want := false
+			for _, w := range tt.want {
+				want = want || w == sentinel
+			}
+			if got := errors.Is(tt.err, sentinel); got != want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, sentinel, got, want)
+			}
+		}
+	}
+}
+
+func TestAPIError_FromClient(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"errorCode":"RL01","errorMessage":"Too many requests"}`))
+	})
+	c.AccountIDs = []string{"111"}
+	_, err := c.GetAccountInfo()
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("errors.As(%v) failed", err)
+	}
+	if apiErr.StatusCode != 429 || apiErr.Code != "RL01" || apiErr.Message != "Too many requests" {
+		t.Errorf("apiErr = %+v", apiErr)
+	}
+	if !strings.HasSuffix(apiErr.Endpoint, "/HoldingV2") || !strings.HasPrefix(apiErr.Endpoint, "GET ") {
+		t.Errorf("Endpoint = %q", apiErr.Endpoint)
+	}
+	if !strings.Contains(string(apiErr.Body()), "RL01") {
+		t.Errorf("Body() = %s", apiErr.Body())
+	}
+}
+
+func TestPlaceOrder_Errors(t *testing.T) {
+	order := OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Buy", Quantity: 1, DryRun: true}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"orderStrategy":{"orderId":1,"orderReturnCode":20,"orderMessages":[{"message":"Insufficient buying power"}]}}`))
+	})
+	result, err := c.PlaceOrder(order)
+	if !errors.Is(err, ErrOrderRejected) || result.Verified {
+		t.Fatalf("rejected order: result=%+v err=%v", result, err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "20" || apiErr.Message != "Insufficient buying power" {
+		t.Errorf("apiErr = %+v", apiErr)
+	}
+	if len(result.VerifyMessages) != 1 {
+		t.Errorf("messages = %v", result.VerifyMessages)
+	}
+
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	})
+	result, err = c.PlaceOrder(order)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 || result.Verified || result.VerifyMessages != nil {
+		t.Errorf("server error: result=%+v err=%v", result, err)
+	}
+}
+
+func TestUpdateToken_SessionExpired(t *testing.T) {
+	c := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	err := c.UpdateToken("api")
+	if !errors.Is(err, ErrSessionExpired) || !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrSessionExpired and ErrUnauthorized", err)
+	}
+}
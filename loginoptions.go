@@ -0,0 +1,139 @@
+package schwab
+
+import (
+	"context"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// LoginOptions configures the Playwright browser used by Login.
+// Zero values fall back to the defaults from DefaultLoginOptions.
+type LoginOptions struct {
+	// Headless runs Chromium without a window (required on servers without a display).
+	Headless bool
+	// ExecutablePath uses a specific Chromium/Chrome binary instead of Playwright's bundled one.
+	ExecutablePath string
+	UserAgent      string
+	ViewportWidth  int
+	ViewportHeight int
+	// Proxy routes the browser through a proxy server.
+	Proxy *ProxyOptions
+	// SlowMo slows every Playwright operation down by the given duration (useful for debugging).
+	SlowMo time.Duration
+	// DiagnosticsDir, if set, records a Playwright trace during login and, when the login fails,
+	// saves it with a screenshot, the page HTML and the URL in a new subdirectory.
+	// The trace includes the typed credentials; the files are readable only by the owner.
+	DiagnosticsDir string
+
+	// NavigationTimeout bounds loading the Schwab homepage.
+	NavigationTimeout time.Duration
+	// SelectorTimeout bounds waiting for the login iframe and the trade page.
+	SelectorTimeout time.Duration
+	// ReloadTimeout bounds each page refresh.
+	ReloadTimeout time.Duration
+	// TokenTimeout bounds waiting for the authorization header after submitting credentials.
+	TokenTimeout time.Duration
+	// TradePageTimeout bounds waiting for the redirect to the trade page.
+	TradePageTimeout time.Duration
+	// ChallengeTimeout bounds each extra verification step (entering a security code, approving a push).
+	ChallengeTimeout time.Duration
+	// PostLoginDelay is how long to watch for a verification screen after submitting credentials
+	// before refreshing.
+	PostLoginDelay time.Duration
+	// CookieSettleDelay is the pause before reading cookies after the final refresh.
+	CookieSettleDelay time.Duration
+
+	// OnSecurityCode is called when Schwab asks for a one-time security code (e.g. sent by SMS).
+	// It should return the code, typically by prompting the user. Without it such logins fail
+	// with ErrSecurityCodeRequired.
+	OnSecurityCode func(ctx context.Context) (string, error)
+	// OnLoginState, if set, is called as the login moves through verification steps,
+	// e.g. LoginStateAwaitingPushApproval while waiting for a push notification to be approved.
+	OnLoginState func(LoginState)
+}
+
+// ProxyOptions configures a browser proxy, e.g. Server "http://proxy:3128".
+type ProxyOptions struct {
+	Server   string
+	Bypass   string
+	Username string
+	Password string
+}
+
+// DefaultLoginOptions returns the options Login uses when none are set.
+func DefaultLoginOptions() LoginOptions {
+	return LoginOptions{
+		UserAgent:         "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		ViewportWidth:     1920,
+		ViewportHeight:    1080,
+		NavigationTimeout: 60 * time.Second,
+		SelectorTimeout:   30 * time.Second,
+		ReloadTimeout:     30 * time.Second,
+		TokenTimeout:      60 * time.Second,
+		TradePageTimeout:  60 * time.Second,
+		ChallengeTimeout:  2 * time.Minute,
+		PostLoginDelay:    5 * time.Second,
+		CookieSettleDelay: 1500 * time.Millisecond,
+	}
+}
+
+// withDefaults fills zero fields from DefaultLoginOptions.
+func (o LoginOptions) withDefaults() LoginOptions {
+	d := DefaultLoginOptions()
+	if o.UserAgent == "" {
+		o.UserAgent = d.UserAgent
+	}
+	if o.ViewportWidth == 0 || o.ViewportHeight == 0 {
+		o.ViewportWidth, o.ViewportHeight = d.ViewportWidth, d.ViewportHeight
+	}
+	for _, f := range []struct{ v, def *time.Duration }{
+		{&o.NavigationTimeout, &d.NavigationTimeout},
+		{&o.SelectorTimeout, &d.SelectorTimeout},
+		{&o.ReloadTimeout, &d.ReloadTimeout},
+		{&o.TokenTimeout, &d.TokenTimeout},
+		{&o.TradePageTimeout, &d.TradePageTimeout},
+		{&o.ChallengeTimeout, &d.ChallengeTimeout},
+		{&o.PostLoginDelay, &d.PostLoginDelay},
+		{&o.CookieSettleDelay, &d.CookieSettleDelay},
+	} {
+		if *f.v == 0 {
+			*f.v = *f.def
+		}
+	}
+	return o
+}
+
+// launchOptions converts the options to Playwright launch options.
+func (o LoginOptions) launchOptions() playwright.BrowserTypeLaunchOptions {
+	opts := playwright.BrowserTypeLaunchOptions{
+		Headless: playwright.Bool(o.Headless),
+		Args: []string{
+			"--disable-blink-features=AutomationControlled",
+			"--disable-automation",
+		},
+	}
+	if o.ExecutablePath != "" {
+		opts.ExecutablePath = playwright.String(o.ExecutablePath)
+	}
+	if o.SlowMo > 0 {
+		opts.SlowMo = millis(o.SlowMo)
+	}
+	if o.Proxy != nil && o.Proxy.Server != "" {
+		proxy := &playwright.Proxy{Server: o.Proxy.Server}
+		if o.Proxy.Bypass != "" {
+			proxy.Bypass = playwright.String(o.Proxy.Bypass)
+		}
+		if o.Proxy.Username != "" {
+			proxy.Username = playwright.String(o.Proxy.Username)
+			proxy.Password = playwright.String(o.Proxy.Password)
+		}
+		opts.Proxy = proxy
+	}
+	return opts
+}
+
+// millis converts d to the millisecond float Playwright expects for timeouts.
+func millis(d time.Duration) *float64 {
+	return playwright.Float(float64(d.Milliseconds()))
+}
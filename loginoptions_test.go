@@ -0,0 +1,52 @@
+package schwab
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoginOptions_WithDefaults(t *testing.T) {
+	got := LoginOptions{}.withDefaults()
+	if !reflect.DeepEqual(got, DefaultLoginOptions()) {
+		t.Errorf("zero options = %+v, want defaults", got)
+	}
+	if got.Headless {
+		t.Error("Headless should default to false")
+	}
+
+	custom := LoginOptions{Headless: true, UserAgent: "ua", TokenTimeout: 2 * time.Minute}.withDefaults()
+	if !custom.Headless || custom.UserAgent != "ua" || custom.TokenTimeout != 2*time.Minute {
+		t.Errorf("explicit fields overwritten: %+v", custom)
+	}
+	if custom.NavigationTimeout != 60*time.Second || custom.ViewportWidth != 1920 {
+		t.Errorf("unset fields not defaulted: %+v", custom)
+	}
+}
+
+func TestLoginOptions_LaunchOptions(t *testing.T) {
+	o := LoginOptions{
+		Headless:       true,
+		ExecutablePath: "/usr/bin/chromium",
+		SlowMo:         250 * time.Millisecond,
+		Proxy:          &ProxyOptions{Server: "http://proxy:3128", Username: "u", Password: "p"},
+	}.withDefaults()
+	got := o.launchOptions()
+	if got.Headless == nil || !*got.Headless {
+		t.Error("Headless not set")
+	}
+	if got.ExecutablePath == nil || *got.ExecutablePath != "/usr/bin/chromium" {
+		t.Errorf("ExecutablePath = %v", got.ExecutablePath)
+	}
+	if got.SlowMo == nil || *got.SlowMo != 250 {
+		t.Errorf("SlowMo = %v, want 250", got.SlowMo)
+	}
+	if got.Proxy == nil || got.Proxy.Server != "http://proxy:3128" || *got.Proxy.Username != "u" || *got.Proxy.Password != "p" {
+		t.Errorf("Proxy = %+v", got.Proxy)
+	}
+
+	plain := LoginOptions{}.withDefaults().launchOptions()
+	if plain.ExecutablePath != nil || plain.SlowMo != nil || plain.Proxy != nil {
+		t.Errorf("unexpected launch options for defaults: %+v", plain)
+	}
+}
@@ -0,0 +1,52 @@
+package schwab
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetLotsForSymbol(t *testing.T) {
+	var gotSSID, gotIDs string
+	var holdingsIDs []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/HoldingV2") {
+			holdingsIDs = append(holdingsIDs, r.Header.Get("Schwab-Client-Ids"))
+			w.Write([]byte(`{"accounts":[{"accountId":"123","groupedPositions":[{"groupName":"Equities","holdingsRows":[
+				{"symbol":{"symbol":"AAPL","ssId":2024}},{"symbol":{"symbol":"MSFT","ssId":7}}]}]}]}`))
+			return
+		}
+		gotSSID = r.URL.Query().Get("ssid")
+		gotIDs = r.Header.Get("Schwab-Client-Ids")
+		w.Write([]byte(`{"lots":[
+			{"openDate":"2020-01-02","qty":10,"costPerShare":75,"costBasis":750,"marketValue":1800,"gainLoss":1050,"holdingPeriod":"Long Term"},
+			{"openDate":"2099-01-02","qty":"2","costBasis":"400.00","marketValue":360,"gainLoss":-40}
+		]}`))
+	})
+
+	c.AccountIDs = []string{"123", "456", "789"}
+
+	lots, err := c.GetLotsForSymbol("123", "aapl")
+	if err != nil {
+		t.Fatalf("GetLotsForSymbol: %v", err)
+	}
+	if len(holdingsIDs) != 1 || holdingsIDs[0] != "123" {
+		t.Errorf("holdings requested for %v, want only account 123", holdingsIDs)
+	}
+	if gotSSID != "2024" || gotIDs != "123" {
+		t.Errorf("ssid = %q, Schwab-Client-Ids = %q", gotSSID, gotIDs)
+	}
+	if len(lots) != 2 {
+		t.Fatalf("got %d lots, want 2", len(lots))
+	}
+	if lots[0].HoldingPeriod != HoldingPeriodLongTerm || lots[0].TotalCost != 750 || lots[0].AcquiredDate.Year() != 2020 {
+		t.Errorf("unexpected lot 0: %+v", lots[0])
+	}
+	if lots[1].HoldingPeriod != HoldingPeriodShortTerm || lots[1].CostPerShare != 200 || lots[1].GainLoss != -40 {
+		t.Errorf("unexpected lot 1: %+v", lots[1])
+	}
+
+	if _, err := c.GetLotsForSymbol("123", "TSLA"); err == nil {
+		t.Error("expected error for symbol not held")
+	}
+}
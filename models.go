@@ -1,8 +1,13 @@
 package schwab
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
 
-// flexString unmarshals a JSON value that may be a string or an object (e.g. {"description":"..."}); returns a string.
+// flexString unmarshals a JSON value that may be a string, a number or an object (e.g. {"description":"..."}); returns a string.
 type flexString string
 
 func (s *flexString) UnmarshalJSON(data []byte) error {
@@ -18,6 +23,10 @@ func (s *flexString) UnmarshalJSON(data []byte) error {
 		*s = flexString(str)
 		return nil
 	}
+	if data[0] == '-' || (data[0] >= '0' && data[0] <= '9') {
+		*s = flexString(data)
+		return nil
+	}
 	if data[0] == '{' {
 		var obj map[string]interface{}
 		if err := json.Unmarshal(data, &obj); err != nil {
@@ -36,6 +45,47 @@ func (s *flexString) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// flexFloat unmarshals a JSON number that may also be sent as a string (e.g. "1,234.50"); empty or invalid values become 0.
+type flexFloat float64
+
+func (f *flexFloat) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(string(data), `"`)
+	str = strings.NewReplacer(",", "", "$", "").Replace(str)
+	if str == "" || str == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		*f = 0
+		return nil
+	}
+	*f = flexFloat(v)
+	return nil
+}
+
+// timeLayouts are the timestamp formats seen in Schwab API responses.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.000",
+	"01/02/2006 15:04:05",
+	"01/02/2006 03:04:05 PM",
+	"01/02/2006",
+	"2006-01-02",
+}
+
+// parseTime parses a Schwab timestamp; it returns the zero time when the value is empty or unrecognized.
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 // AccountInfoV2Response represents the response from PositionsV2Url
 type AccountInfoV2Response struct {
 	Accounts []AccountV2 `json:"accounts"`
@@ -84,6 +134,38 @@ type MarketValInfo struct {
 	Val float64 `json:"val"`
 }
 
+// AccountsV2Response represents the response from AccountInfoV2Url
+type AccountsV2Response struct {
+	Accounts []AccountSummaryV2 `json:"Accounts"`
+}
+
+type AccountSummaryV2 struct {
+	AccountId           string     `json:"AccountId"`
+	NickName            string     `json:"NickName"`
+	AccountType         flexString `json:"AccountType"`
+	AccountColor        int        `json:"AccountColor"`
+	IsTradingAllowed    bool       `json:"IsTradingAllowed"`
+	IsMarginEnabled     bool       `json:"IsMarginEnabled"`
+	OptionsTradingLevel int        `json:"OptionsTradingLevel"`
+}
+
+// Account is one account returned by ListAccounts.
+type Account struct {
+	Number      string             `json:"number"`
+	Nickname    string             `json:"nickname"`
+	Type        AccountType        `json:"type"`
+	RawType     string             `json:"raw_type"` // type as sent by Schwab, e.g. "Roth Contributory IRA"
+	Color       int                `json:"color"`
+	Permissions TradingPermissions `json:"permissions"`
+}
+
+type TradingPermissions struct {
+	Trading bool `json:"trading"`
+	Margin  bool `json:"margin"`
+	// OptionsLevel is 0 when options trading is not approved.
+	OptionsLevel int `json:"options_level"`
+}
+
 // OrderVerificationResponse represents the response for order verification
 type OrderVerificationResponse struct {
 	OrderStrategy OrderStrategy `json:"orderStrategy"`
@@ -94,14 +176,220 @@ type OrderStrategy struct {
 	OrderMessages   []OrderMessage `json:"orderMessages"`
 	OrderReturnCode int            `json:"orderReturnCode"`
 	OrderLegs       []OrderLeg     `json:"orderLegs"`
+	// Estimates returned by the verification step.
+	EstimatedTotalAmount flexFloat `json:"estimatedTotalAmount"`
+	Commission           flexFloat `json:"commission"`
+	Fees                 flexFloat `json:"fees"`
 }
 
 type OrderMessage struct {
-	Message string `json:"message"`
+	Message  string     `json:"message"`
+	Severity flexString `json:"severity"`
 }
 
+// OrderLeg is one leg of a verified order. Legs are matched back to the request by
+// instrument symbol when present, otherwise by position.
 type OrderLeg struct {
-	SchwabSecurityId int64 `json:"schwabSecurityId"`
+	SchwabSecurityId int64              `json:"schwabSecurityId"`
+	Instrument       OrderLegInstrument `json:"instrument"`
+}
+
+type OrderLegInstrument struct {
+	Symbol string `json:"symbol"`
+}
+
+// OrdersV2Response represents the response from OrdersV2Url
+type OrdersV2Response struct {
+	Orders []OrderGroup `json:"Orders"`
+}
+
+type OrderGroup struct {
+	OrderList []OrderStatusDetail `json:"OrderList"`
+}
+
+type OrderStatusDetail struct {
+	OrderId       int64            `json:"OrderId"`
+	AccountNumber string           `json:"AccountNumber"`
+	Status        flexString       `json:"DisplayStatus"`
+	LimitPrice    flexFloat        `json:"LimitPrice"`
+	StopPrice     flexFloat        `json:"StopPrice"`
+	EnteredTime   string           `json:"EnteredTime"`
+	ExecutedTime  string           `json:"ExecutedTime"`
+	OrderLegs     []OrderStatusLeg `json:"OrderLegs"`
+}
+
+type OrderStatusLeg struct {
+	Symbol         string     `json:"Symbol"`
+	SecurityType   flexFloat  `json:"SecurityType"`
+	Action         flexString `json:"Action"`
+	Quantity       flexFloat  `json:"Quantity"`
+	FilledQuantity flexFloat  `json:"FilledQuantity"`
+}
+
+// Order is one open or historical order returned by GetOrders.
+type Order struct {
+	OrderID        int64          `json:"order_id"`
+	AccountID      string         `json:"account_id"`
+	Symbol         string         `json:"symbol"`
+	Side           string         `json:"side"`
+	Quantity       float64        `json:"quantity"`
+	FilledQuantity float64        `json:"filled_quantity"`
+	LimitPrice     float64        `json:"limit_price"`
+	StopPrice      float64        `json:"stop_price"`
+	Status         string         `json:"status"`
+	EnteredTime    time.Time      `json:"entered_time"`
+	ExecutedTime   time.Time      `json:"executed_time"`
+	InstrumentType InstrumentType `json:"instrument_type"` // InstrumentStock or InstrumentOption; used by CancelAllOpenOrders
+}
+
+// CancelOrderV2Response represents the response from CancelOrderV2Url
+type CancelOrderV2Response struct {
+	CancelOrderConfirmation CancelOrderConfirmation `json:"CancelOrderConfirmation"`
+}
+
+type CancelOrderConfirmation struct {
+	OrderId         int64          `json:"OrderId"`
+	IsCancelSuccess bool           `json:"IsCancelSuccess"`
+	OrderMessages   []OrderMessage `json:"OrderMessages"`
+}
+
+// QuotesV2Response represents the response from TickerQuotesV2Url
+type QuotesV2Response struct {
+	Quotes []QuoteV2 `json:"quotes"`
+}
+
+type QuoteV2 struct {
+	Symbol string      `json:"symbol"`
+	Quote  QuoteDetail `json:"quote"`
+}
+
+type QuoteDetail struct {
+	Last          flexFloat `json:"last"`
+	Bid           flexFloat `json:"bid"`
+	Ask           flexFloat `json:"ask"`
+	BidSize       flexFloat `json:"bidSize"`
+	AskSize       flexFloat `json:"askSize"`
+	Volume        flexFloat `json:"volume"`
+	NetChange     flexFloat `json:"netChange"`
+	PercentChange flexFloat `json:"percentChange"`
+	High52        flexFloat `json:"high52"`
+	Low52         flexFloat `json:"low52"`
+	QuoteTime     string    `json:"quoteTime"`
+}
+
+// Quote is a snapshot quote returned by GetQuotes.
+type Quote struct {
+	Symbol        string    `json:"symbol"`
+	Last          float64   `json:"last"`
+	Bid           float64   `json:"bid"`
+	Ask           float64   `json:"ask"`
+	BidSize       float64   `json:"bid_size"`
+	AskSize       float64   `json:"ask_size"`
+	Volume        float64   `json:"volume"`
+	NetChange     float64   `json:"net_change"`
+	PercentChange float64   `json:"percent_change"`
+	High52Week    float64   `json:"high_52_week"`
+	Low52Week     float64   `json:"low_52_week"`
+	QuoteTime     time.Time `json:"quote_time"`
+}
+
+// LotsV2Response represents the response from LotDetailsV2Url
+type LotsV2Response struct {
+	Lots []LotDetail `json:"lots"`
+}
+
+type LotDetail struct {
+	OpenDate      string     `json:"openDate"`
+	Qty           flexFloat  `json:"qty"`
+	CostPerShare  flexFloat  `json:"costPerShare"`
+	CostBasis     flexFloat  `json:"costBasis"`
+	MarketValue   flexFloat  `json:"marketValue"`
+	GainLoss      flexFloat  `json:"gainLoss"`
+	HoldingPeriod flexString `json:"holdingPeriod"`
+}
+
+// Lot is one open tax lot returned by GetLots.
+type Lot struct {
+	AcquiredDate  time.Time     `json:"acquired_date"`
+	Quantity      float64       `json:"quantity"`
+	CostPerShare  float64       `json:"cost_per_share"`
+	TotalCost     float64       `json:"total_cost"`
+	MarketValue   float64       `json:"market_value"`
+	GainLoss      float64       `json:"gain_loss"`
+	HoldingPeriod HoldingPeriod `json:"holding_period"`
+}
+
+// OptionChainsV2Response represents the response from OptionChainsV2Url
+type OptionChainsV2Response struct {
+	UnderlyingData struct {
+		Symbol string `json:"Symbol"`
+	} `json:"UnderlyingData"`
+	Expirations []OptionExpirationV2 `json:"Expirations"`
+}
+
+type OptionExpirationV2 struct {
+	ExpirationGroup struct {
+		ExpirationDate string `json:"ExpirationDate"`
+	} `json:"ExpirationGroup"`
+	Chains []struct {
+		Legs []OptionLegV2 `json:"Legs"`
+	} `json:"Chains"`
+}
+
+type OptionLegV2 struct {
+	Symbol            string    `json:"Symbol"`
+	OptionType        string    `json:"OptionType"` // "C" or "P"
+	Strike            flexFloat `json:"Strike"`
+	BidPrice          flexFloat `json:"BidPrice"`
+	AskPrice          flexFloat `json:"AskPrice"`
+	LastPrice         flexFloat `json:"LastPrice"`
+	Volume            flexFloat `json:"Volume"`
+	OpenInterest      flexFloat `json:"OpenInterest"`
+	ImpliedVolatility flexFloat `json:"ImpliedVolatility"`
+	Delta             flexFloat `json:"Delta"`
+	Gamma             flexFloat `json:"Gamma"`
+	Theta             flexFloat `json:"Theta"`
+	Vega              flexFloat `json:"Vega"`
+	Rho               flexFloat `json:"Rho"`
+}
+
+// OptionChain is the chain returned by GetOptionChain, grouped by expiration then strike.
+type OptionChain struct {
+	Underlying  string             `json:"underlying"`
+	Expirations []OptionExpiration `json:"expirations"`
+}
+
+type OptionExpiration struct {
+	Date    time.Time      `json:"date"`
+	Strikes []OptionStrike `json:"strikes"`
+}
+
+// OptionStrike holds the call and put at one strike; either may be nil when filtered out or not listed.
+type OptionStrike struct {
+	Strike float64         `json:"strike"`
+	Call   *OptionContract `json:"call,omitempty"`
+	Put    *OptionContract `json:"put,omitempty"`
+}
+
+type OptionContract struct {
+	Symbol            string      `json:"symbol"`
+	Right             OptionRight `json:"right"`
+	Bid               float64     `json:"bid"`
+	Ask               float64     `json:"ask"`
+	Last              float64     `json:"last"`
+	Volume            float64     `json:"volume"`
+	OpenInterest      float64     `json:"open_interest"`
+	ImpliedVolatility float64     `json:"implied_volatility"`
+	Greeks            *Greeks     `json:"greeks,omitempty"`
+}
+
+// Greeks are only populated when Schwab returns them (OptionChainOptions.IncludeGreeks).
+type Greeks struct {
+	Delta float64 `json:"delta"`
+	Gamma float64 `json:"gamma"`
+	Theta float64 `json:"theta"`
+	Vega  float64 `json:"vega"`
+	Rho   float64 `json:"rho"`
 }
 
 // AccountInfoV2Compat matches the Python schwab-api get_account_info_v2() shape for 1:1 porting.
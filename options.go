@@ -0,0 +1,334 @@
+package schwab
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OptionRight is the type of an option contract.
+type OptionRight string
+
+const (
+	OptionCall OptionRight = "Call"
+	OptionPut  OptionRight = "Put"
+)
+
+// OptionInstruction opens or closes an option position.
+type OptionInstruction string
+
+const (
+	BuyToOpen   OptionInstruction = "BuyToOpen"
+	BuyToClose  OptionInstruction = "BuyToClose"
+	SellToOpen  OptionInstruction = "SellToOpen"
+	SellToClose OptionInstruction = "SellToClose"
+)
+
+// optionInstructionCodes maps each OptionInstruction to the leg Instruction code Schwab expects.
+var optionInstructionCodes = map[OptionInstruction]string{
+	BuyToOpen:   "201",
+	BuyToClose:  "202",
+	SellToOpen:  "203",
+	SellToClose: "204",
+}
+
+// OptionContractID identifies an option contract, either by OCC symbol
+// (e.g. "AAPL  240315C00150000") or by underlying, expiration, strike and right.
+type OptionContractID struct {
+	OCCSymbol  string
+	Underlying string
+	Expiration time.Time
+	Strike     float64
+	Right      OptionRight
+}
+
+// Symbol returns the OCC symbol for the contract: the root padded to six characters,
+// the expiration as YYMMDD, C or P, and the strike times 1000 in eight digits.
+func (id OptionContractID) Symbol() (string, error) {
+	if id.OCCSymbol != "" {
+		return strings.TrimSpace(id.OCCSymbol), nil
+	}
+	if id.Underlying == "" || len(id.Underlying) > 6 {
+		return "", fmt.Errorf("option underlying must be 1-6 characters, got %q", id.Underlying)
+	}
+	if id.Expiration.IsZero() {
+		return "", fmt.Errorf("option expiration is required")
+	}
+	if id.Strike <= 0 {
+		return "", fmt.Errorf("option strike must be positive")
+	}
+	var right string
+	switch id.Right {
+	case OptionCall:
+		right = "C"
+	case OptionPut:
+		right = "P"
+	default:
+		return "", fmt.Errorf("option right must be %s or %s", OptionCall, OptionPut)
+	}
+	return fmt.Sprintf("%-6s%s%s%08d", strings.ToUpper(id.Underlying), id.Expiration.Format("060102"), right,
+		int64(id.Strike*1000+0.5)), nil
+}
+
+// OptionOrderRequest describes a single-leg option order for PlaceOptionOrder.
+type OptionOrderRequest struct {
+	AccountID   string
+	Contract    OptionContractID
+	Instruction OptionInstruction
+	// Quantity is the number of contracts.
+	Quantity float64
+	// Type is OrderTypeMarket (default) or OrderTypeLimit; stops are not offered for options.
+	Type       OrderType
+	LimitPrice float64
+	// Duration is DurationDay (default) or DurationGTC.
+	Duration Duration
+	DryRun   bool
+}
+
+// validate checks the request and returns the contract's OCC symbol.
+func (o OptionOrderRequest) validate() (string, error) {
+	if _, ok := optionInstructionCodes[o.Instruction]; !ok {
+		return "", fmt.Errorf("unsupported option instruction %q", o.Instruction)
+	}
+	if o.Quantity <= 0 {
+		return "", fmt.Errorf("quantity must be positive")
+	}
+	if err := validateOptionPricing(o.Type, o.LimitPrice, o.Duration); err != nil {
+		return "", err
+	}
+	return o.Contract.Symbol()
+}
+
+// validateOptionPricing applies the order type and duration rules shared by option orders.
+func validateOptionPricing(orderType OrderType, limitPrice float64, duration Duration) error {
+	switch orderType {
+	case "", OrderTypeMarket:
+		if limitPrice != 0 {
+			return fmt.Errorf("%s order does not take a limit price", OrderTypeMarket)
+		}
+	case OrderTypeLimit:
+		if limitPrice <= 0 {
+			return fmt.Errorf("%s order requires a positive limit price", OrderTypeLimit)
+		}
+	default:
+		return fmt.Errorf("option orders do not support order type %q", orderType)
+	}
+	switch duration {
+	case "", DurationDay, DurationGTC:
+	default:
+		return fmt.Errorf("option orders do not support duration %q", duration)
+	}
+	return nil
+}
+
+// optionOrderStrategy builds the OrderStrategy shared by single- and multi-leg option orders.
+func optionOrderStrategy(orderTypeCode string, limitPrice float64, duration Duration, strategyType int, legs []map[string]interface{}) map[string]interface{} {
+	if duration == "" {
+		duration = DurationDay
+	}
+	return map[string]interface{}{
+		"PrimarySecurityType": 48, // Option
+		"CostBasisRequest":    nil,
+		"OrderType":           orderTypeCode,
+		"LimitPrice":          formatPrice(limitPrice),
+		"StopPrice":           "0",
+		"Duration":            durationCodes[duration],
+		"AllNoneIn":           false,
+		"DoNotReduceIn":       false,
+		"OrderStrategyType":   strategyType,
+		"OrderLegs":           legs,
+	}
+}
+
+// optionLeg builds one OrderLegs entry for an option contract.
+func optionLeg(symbol string, instruction OptionInstruction, qty float64) map[string]interface{} {
+	return map[string]interface{}{
+		"Quantity":       fmt.Sprintf("%f", qty),
+		"LeavesQuantity": fmt.Sprintf("%f", qty),
+		"Instrument":     map[string]interface{}{"Symbol": symbol},
+		"SecurityType":   48,
+		"Instruction":    optionInstructionCodes[instruction],
+	}
+}
+
+// payload builds the OrderVerificationV2Url request body for the verification step.
+func (o OptionOrderRequest) payload(symbol string) map[string]interface{} {
+	orderType := o.Type
+	if orderType == "" {
+		orderType = OrderTypeMarket
+	}
+	return map[string]interface{}{
+		"UserContext": map[string]interface{}{
+			"AccountId":    o.AccountID,
+			"AccountColor": 0,
+		},
+		"OrderStrategy": optionOrderStrategy(orderTypeCodes[orderType], o.LimitPrice, o.Duration, 1,
+			[]map[string]interface{}{optionLeg(symbol, o.Instruction, o.Quantity)}),
+		"OrderProcessingControl": 1, // Verification
+	}
+}
+
+// PlaceOptionOrder verifies a single-leg option order and, unless order.DryRun is set, executes it.
+// It uses the same verify/execute round trip as PlaceOrder.
+func (c *Client) PlaceOptionOrder(order OptionOrderRequest) (TradeResult, error) {
+	return c.PlaceOptionOrderContext(context.Background(), order)
+}
+
+// PlaceOptionOrderContext is like PlaceOptionOrder but uses ctx for cancellation and deadlines.
+func (c *Client) PlaceOptionOrderContext(ctx context.Context, order OptionOrderRequest) (TradeResult, error) {
+	symbol, err := order.validate()
+	if err != nil {
+		return TradeResult{}, err
+	}
+	return c.submitOrder(ctx, order.payload(symbol), order.DryRun)
+}
+
+// OptionChainOptions filters GetOptionChain results. Zero values match everything.
+type OptionChainOptions struct {
+	// FromExpiration and ToExpiration bound the expiration dates (inclusive).
+	FromExpiration time.Time
+	ToExpiration   time.Time
+	// MinStrike and MaxStrike bound the strikes (inclusive); 0 leaves that end open.
+	MinStrike float64
+	MaxStrike float64
+	// Right limits the chain to calls or puts; empty returns both.
+	Right OptionRight
+	// IncludeGreeks asks Schwab to return delta, gamma, theta, vega and rho.
+	IncludeGreeks bool
+}
+
+// GetOptionChain returns the option chain for underlying via OptionChainsV2Url
+// (Python schwab-api get_options_chains_v2()).
+func (c *Client) GetOptionChain(underlying string, opts OptionChainOptions) (*OptionChain, error) {
+	return c.GetOptionChainContext(context.Background(), underlying, opts)
+}
+
+// GetOptionChainContext is like GetOptionChain but uses ctx for cancellation and deadlines.
+func (c *Client) GetOptionChainContext(ctx context.Context, underlying string, opts OptionChainOptions) (*OptionChain, error) {
+	if underlying == "" {
+		return nil, fmt.Errorf("underlying symbol is required")
+	}
+	if err := c.UpdateTokenContext(ctx, "api"); err != nil && c.Debug {
+		log.Printf("UpdateToken(api) warning: %v", err)
+	}
+
+	params := url.Values{}
+	params.Set("Symbol", underlying)
+	params.Set("IncludeGreeks", fmt.Sprintf("%t", opts.IncludeGreeks))
+	u := c.endpoint(OptionChainsV2Url)
+	status, body, err := c.do(ctx, "GET", u+"?"+params.Encode(), nil, map[string]string{
+		"schwab-client-channel":   "IO",
+		"schwab-client-correlid":  newCorrelationID(),
+		"schwab-env":              "PROD",
+		"schwab-resource-version": "1",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status != 200 {
+		return nil, newAPIError("GET", u, status, body)
+	}
+
+	var data OptionChainsV2Response
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	chain := optionChainFromResponse(data, opts)
+	if chain.Underlying == "" {
+		chain.Underlying = underlying
+	}
+	return chain, nil
+}
+
+// optionChainFromResponse groups legs by expiration and strike, dropping anything outside opts.
+func optionChainFromResponse(data OptionChainsV2Response, opts OptionChainOptions) *OptionChain {
+	chain := &OptionChain{Underlying: data.UnderlyingData.Symbol, Expirations: []OptionExpiration{}}
+	for _, exp := range data.Expirations {
+		date := parseTime(exp.ExpirationGroup.ExpirationDate)
+		if !opts.FromExpiration.IsZero() && date.Before(opts.FromExpiration) {
+			continue
+		}
+		if !opts.ToExpiration.IsZero() && date.After(opts.ToExpiration) {
+			continue
+		}
+
+		byStrike := make(map[float64]*OptionStrike)
+		for _, ch := range exp.Chains {
+			for _, leg := range ch.Legs {
+				strike := float64(leg.Strike)
+				if (opts.MinStrike != 0 && strike < opts.MinStrike) || (opts.MaxStrike != 0 && strike > opts.MaxStrike) {
+					continue
+				}
+				contract := optionContractFromLeg(leg)
+				if opts.Right != "" && contract.Right != opts.Right {
+					continue
+				}
+				s, ok := byStrike[strike]
+				if !ok {
+					s = &OptionStrike{Strike: strike}
+					byStrike[strike] = s
+				}
+				if contract.Right == OptionCall {
+					s.Call = contract
+				} else {
+					s.Put = contract
+				}
+			}
+		}
+		if len(byStrike) == 0 {
+			continue
+		}
+
+		expiration := OptionExpiration{Date: date}
+		for _, s := range byStrike {
+			expiration.Strikes = append(expiration.Strikes, *s)
+		}
+		sort.Slice(expiration.Strikes, func(i, j int) bool {
+			return expiration.Strikes[i].Strike < expiration.Strikes[j].Strike
+		})
+		chain.Expirations = append(chain.Expirations, expiration)
+	}
+	return chain
+}
+
+func optionContractFromLeg(leg OptionLegV2) *OptionContract {
+	right := OptionCall
+	if leg.OptionType == "P" || leg.OptionType == "Put" {
+		right = OptionPut
+	}
+	contract := &OptionContract{
+		Symbol:            leg.Symbol,
+		Right:             right,
+		Bid:               float64(leg.BidPrice),
+		Ask:               float64(leg.AskPrice),
+		Last:              float64(leg.LastPrice),
+		Volume:            float64(leg.Volume),
+		OpenInterest:      float64(leg.OpenInterest),
+		ImpliedVolatility: float64(leg.ImpliedVolatility),
+	}
+	greeks := Greeks{
+		Delta: float64(leg.Delta),
+		Gamma: float64(leg.Gamma),
+		Theta: float64(leg.Theta),
+		Vega:  float64(leg.Vega),
+		Rho:   float64(leg.Rho),
+	}
+	if greeks != (Greeks{}) {
+		contract.Greeks = &greeks
+	}
+	return contract
+}
+
+// newCorrelationID returns a random ID for the schwab-client-correlid header.
+func newCorrelationID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
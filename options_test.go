@@ -0,0 +1,149 @@
+package schwab
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const sampleOptionChain = `{"UnderlyingData":{"Symbol":"AAPL"},"Expirations":[
+	{"ExpirationGroup":{"ExpirationDate":"2024-03-15"},"Chains":[{"Legs":[
+		{"Symbol":"AAPL  240315C00150000","OptionType":"C","Strike":150,"BidPrice":30.1,"AskPrice":30.5,"LastPrice":30.3,"Volume":120,"OpenInterest":4000,"ImpliedVolatility":0.25,"Delta":0.9,"Gamma":0.01},
+		{"Symbol":"AAPL  240315P00150000","OptionType":"P","Strike":150,"BidPrice":0.1,"AskPrice":0.12,"Volume":50,"OpenInterest":9000,"ImpliedVolatility":0.3},
+		{"Symbol":"AAPL  240315C00200000","OptionType":"C","Strike":"200.00","BidPrice":0.5,"AskPrice":0.6}
+	]}]},
+	{"ExpirationGroup":{"ExpirationDate":"2024-06-21"},"Chains":[{"Legs":[
+		{"Symbol":"AAPL  240621C00150000","OptionType":"C","Strike":150,"BidPrice":33,"AskPrice":33.5}
+	]}]}
+]}`
+
+func TestGetOptionChain(t *testing.T) {
+	var gotGreeks string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotGreeks = r.URL.Query().Get("IncludeGreeks")
+		w.Write([]byte(sampleOptionChain))
+	})
+
+	chain, err := c.GetOptionChain("AAPL", OptionChainOptions{IncludeGreeks: true})
+	if err != nil {
+		t.Fatalf("GetOptionChain: %v", err)
+	}
+	if gotGreeks != "true" {
+		t.Errorf("IncludeGreeks = %q, want true", gotGreeks)
+	}
+	if chain.Underlying != "AAPL" || len(chain.Expirations) != 2 {
+		t.Fatalf("unexpected chain: %+v", chain)
+	}
+	first := chain.Expirations[0]
+	if len(first.Strikes) != 2 || first.Strikes[0].Strike != 150 || first.Strikes[1].Strike != 200 {
+		t.Fatalf("unexpected strikes: %+v", first.Strikes)
+	}
+	call, put := first.Strikes[0].Call, first.Strikes[0].Put
+	if call == nil || put == nil {
+		t.Fatal("expected call and put at 150")
+	}
+	if call.Bid != 30.1 || call.OpenInterest != 4000 || call.Greeks == nil || call.Greeks.Delta != 0.9 {
+		t.Errorf("unexpected call: %+v", call)
+	}
+	if put.Right != OptionPut || put.Greeks != nil {
+		t.Errorf("unexpected put: %+v", put)
+	}
+}
+
+func TestGetOptionChain_Filters(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(sampleOptionChain))
+	})
+	chain, err := c.GetOptionChain("AAPL", OptionChainOptions{
+		ToExpiration: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		MaxStrike:    175,
+		Right:        OptionCall,
+	})
+	if err != nil {
+		t.Fatalf("GetOptionChain: %v", err)
+	}
+	if len(chain.Expirations) != 1 || len(chain.Expirations[0].Strikes) != 1 {
+		t.Fatalf("unexpected chain: %+v", chain)
+	}
+	s := chain.Expirations[0].Strikes[0]
+	if s.Strike != 150 || s.Call == nil || s.Put != nil {
+		t.Errorf("unexpected strike: %+v", s)
+	}
+}
+
+func TestOptionContractID_Symbol(t *testing.T) {
+	id := OptionContractID{Underlying: "aapl", Expiration: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), Strike: 152.5, Right: OptionPut}
+	got, err := id.Symbol()
+	if err != nil {
+		t.Fatalf("Symbol: %v", err)
+	}
+	if want := "AAPL  240315P00152500"; got != want {
+		t.Errorf("Symbol() = %q, want %q", got, want)
+	}
+	if got, _ := (OptionContractID{OCCSymbol: " SPY   240621C00500000 "}).Symbol(); got != "SPY   240621C00500000" {
+		t.Errorf("OCC passthrough = %q", got)
+	}
+	if _, err := (OptionContractID{Underlying: "AAPL", Expiration: id.Expiration, Strike: 150}).Symbol(); err == nil {
+		t.Error("expected error without right")
+	}
+}
+
+func TestPlaceOptionOrder(t *testing.T) {
+	var requests []map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+		w.Write([]byte(`{"orderStrategy":{"orderId":77,"orderReturnCode":0,"orderLegs":[{"schwabSecurityId":9001}],"orderMessages":[{"message":"ok"}]}}`))
+	})
+
+	result, err := c.PlaceOptionOrder(OptionOrderRequest{
+		AccountID:   "123",
+		Contract:    OptionContractID{OCCSymbol: "AAPL  240315C00150000"},
+		Instruction: SellToClose,
+		Quantity:    2,
+		Type:        OrderTypeLimit,
+		LimitPrice:  3.1,
+		Duration:    DurationGTC,
+	})
+	if err != nil || !result.Placed || result.OrderID != 77 {
+		t.Fatalf("PlaceOptionOrder: result=%+v err=%v", result, err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want verify + execute", len(requests))
+	}
+	strategy := requests[0]["OrderStrategy"].(map[string]interface{})
+	if strategy["PrimarySecurityType"] != 48.0 || strategy["OrderType"] != "50" || strategy["LimitPrice"] != "3.1" || strategy["Duration"] != "49" {
+		t.Errorf("unexpected strategy: %v", strategy)
+	}
+	leg := strategy["OrderLegs"].([]interface{})[0].(map[string]interface{})
+	if leg["SecurityType"] != 48.0 || leg["Instruction"] != "204" {
+		t.Errorf("unexpected leg: %v", leg)
+	}
+	exec := requests[1]
+	if exec["OrderProcessingControl"] != 2.0 {
+		t.Errorf("OrderProcessingControl = %v, want 2", exec["OrderProcessingControl"])
+	}
+	execLeg := exec["OrderStrategy"].(map[string]interface{})["OrderLegs"].([]interface{})[0].(map[string]interface{})
+	if execLeg["Instrument"].(map[string]interface{})["ItemIssueId"] != 9001.0 {
+		t.Errorf("ItemIssueId not set: %v", execLeg)
+	}
+}
+
+func TestPlaceOptionOrder_Invalid(t *testing.T) {
+	c := NewClient(false)
+	contract := OptionContractID{OCCSymbol: "AAPL  240315C00150000"}
+	bad := []OptionOrderRequest{
+		{Contract: contract, Instruction: "Hold", Quantity: 1},
+		{Contract: contract, Instruction: BuyToOpen},
+		{Contract: contract, Instruction: BuyToOpen, Quantity: 1, Type: OrderTypeStop},
+		{Contract: contract, Instruction: BuyToOpen, Quantity: 1, Duration: DurationExtendedHours},
+		{Instruction: BuyToOpen, Quantity: 1},
+	}
+	for _, o := range bad {
+		if _, err := c.PlaceOptionOrder(o); err == nil {
+			t.Errorf("PlaceOptionOrder(%+v) = nil error", o)
+		}
+	}
+}
@@ -0,0 +1,477 @@
+package schwab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+// OrderType selects how an order is priced.
+type OrderType string
+
+const (
+	OrderTypeMarket    OrderType = "Market"
+	OrderTypeLimit     OrderType = "Limit"
+	OrderTypeStop      OrderType = "Stop"
+	OrderTypeStopLimit OrderType = "StopLimit"
+)
+
+// orderTypeCodes maps each OrderType to the code Schwab expects in OrderStrategy.OrderType.
+var orderTypeCodes = map[OrderType]string{
+	OrderTypeMarket:    "49",
+	OrderTypeLimit:     "50",
+	OrderTypeStop:      "51",
+	OrderTypeStopLimit: "52",
+}
+
+// Duration selects how long an order stays working and in which session.
+type Duration string
+
+const (
+	DurationDay               Duration = "Day"
+	DurationGTC               Duration = "GTC"
+	DurationFillOrKill        Duration = "FillOrKill"
+	DurationImmediateOrCancel Duration = "ImmediateOrCancel"
+	// DurationExtendedHours works the order in the after-hours session only.
+	DurationExtendedHours Duration = "ExtendedHours"
+	// DurationDayPlusExtended works the order in the regular and extended sessions.
+	DurationDayPlusExtended Duration = "DayPlusExtended"
+)
+
+// durationCodes maps each Duration to the code Schwab expects in OrderStrategy.Duration.
+var durationCodes = map[Duration]string{
+	DurationDay:               "48",
+	DurationGTC:               "49",
+	DurationExtendedHours:     "50",
+	DurationFillOrKill:        "51",
+	DurationImmediateOrCancel: "52",
+	DurationDayPlusExtended:   "201",
+}
+
+// allowedDurations lists the durations the trade ticket offers for each order type.
+// Market orders are day-only; stops cannot run outside the regular session.
+var allowedDurations = map[OrderType]map[Duration]bool{
+	OrderTypeMarket: {DurationDay: true},
+	OrderTypeLimit: {
+		DurationDay: true, DurationGTC: true, DurationFillOrKill: true, DurationImmediateOrCancel: true,
+		DurationExtendedHours: true, DurationDayPlusExtended: true,
+	},
+	OrderTypeStop:      {DurationDay: true, DurationGTC: true},
+	OrderTypeStopLimit: {DurationDay: true, DurationGTC: true},
+}
+
+// OrderRequest describes a stock order for PlaceOrder.
+type OrderRequest struct {
+	AccountID string
+	Symbol    string
+	Side      string // "Buy" or "Sell"
+	Quantity  float64
+	// Type defaults to OrderTypeMarket when empty.
+	Type OrderType
+	// LimitPrice is required for Limit and StopLimit orders.
+	LimitPrice float64
+	// StopPrice is required for Stop and StopLimit orders.
+	StopPrice float64
+	// Duration defaults to DurationDay when empty.
+	Duration Duration
+	// DryRun only verifies the order without executing it.
+	DryRun bool
+}
+
+// validate checks the request for combinations Schwab would reject.
+func (o OrderRequest) validate() error {
+	if o.Side != "Buy" && o.Side != "Sell" {
+		return fmt.Errorf("side must be 'Buy' or 'Sell'")
+	}
+	if o.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+	if o.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+	orderType := o.orderType()
+	if _, ok := orderTypeCodes[orderType]; !ok {
+		return fmt.Errorf("unsupported order type %q", orderType)
+	}
+	needsLimit := orderType == OrderTypeLimit || orderType == OrderTypeStopLimit
+	needsStop := orderType == OrderTypeStop || orderType == OrderTypeStopLimit
+	if needsLimit && o.LimitPrice <= 0 {
+		return fmt.Errorf("%s order requires a positive limit price", orderType)
+	}
+	if !needsLimit && o.LimitPrice != 0 {
+		return fmt.Errorf("%s order does not take a limit price", orderType)
+	}
+	if needsStop && o.StopPrice <= 0 {
+		return fmt.Errorf("%s order requires a positive stop price", orderType)
+	}
+	if !needsStop && o.StopPrice != 0 {
+		return fmt.Errorf("%s order does not take a stop price", orderType)
+	}
+	duration := o.duration()
+	if _, ok := durationCodes[duration]; !ok {
+		return fmt.Errorf("unsupported duration %q", duration)
+	}
+	if !allowedDurations[orderType][duration] {
+		return fmt.Errorf("%s order cannot use duration %s", orderType, duration)
+	}
+	return nil
+}
+
+func (o OrderRequest) orderType() OrderType {
+	if o.Type == "" {
+		return OrderTypeMarket
+	}
+	return o.Type
+}
+
+func (o OrderRequest) duration() Duration {
+	if o.Duration == "" {
+		return DurationDay
+	}
+	return o.Duration
+}
+
+// payload builds the OrderVerificationV2Url request body for the verification step.
+func (o OrderRequest) payload() map[string]interface{} {
+	buySellCode := "49"
+	if o.Side == "Sell" {
+		buySellCode = "50"
+	}
+	return map[string]interface{}{
+		"UserContext": map[string]interface{}{
+			"AccountId":    o.AccountID,
+			"AccountColor": 0,
+		},
+		"OrderStrategy": map[string]interface{}{
+			"PrimarySecurityType": 46, // Stock
+			"CostBasisRequest": map[string]interface{}{
+				"costBasisMethod":        "FIFO",
+				"defaultCostBasisMethod": "FIFO",
+			},
+			"OrderType":         orderTypeCodes[o.orderType()],
+			"LimitPrice":        formatPrice(o.LimitPrice),
+			"StopPrice":         formatPrice(o.StopPrice),
+			"Duration":          durationCodes[o.duration()],
+			"AllNoneIn":         false,
+			"DoNotReduceIn":     false,
+			"OrderStrategyType": 1,
+			"OrderLegs": []map[string]interface{}{
+				{
+					"Quantity":       fmt.Sprintf("%f", o.Quantity),
+					"LeavesQuantity": fmt.Sprintf("%f", o.Quantity),
+					"Instrument":     map[string]interface{}{"Symbol": o.Symbol},
+					"SecurityType":   46,
+					"Instruction":    buySellCode,
+				},
+			},
+		},
+		"OrderProcessingControl": 1, // Verification
+	}
+}
+
+// formatPrice renders a price the way the trade ticket sends it ("0" when unset).
+func formatPrice(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// TradeResult is the outcome of placing (or dry-run verifying) an order.
+type TradeResult struct {
+	// OrderID is the ID Schwab assigned at verification (and kept on execution).
+	OrderID int64
+	// ReturnCode is the OrderReturnCode of the last step that ran.
+	ReturnCode int
+	// VerifyMessages and ExecMessages are the messages from the verification and execution steps.
+	VerifyMessages []TradeMessage
+	ExecMessages   []TradeMessage
+	// EstimatedCost, Commission and Fees are Schwab's estimates from the verification step.
+	EstimatedCost float64
+	Commission    float64
+	Fees          float64
+	// Verified reports whether verification passed; Placed whether the order was executed.
+	// A dry run that passes is Verified but not Placed.
+	Verified bool
+	Placed   bool
+	// Acknowledged reports whether verification warnings were acknowledged under the client's AckPolicy.
+	Acknowledged bool
+}
+
+// TradeMessage is one message from an order response, with Schwab's severity as sent.
+type TradeMessage struct {
+	Message  string
+	Severity string
+	Class    MessageClass
+}
+
+// Messages returns the message texts from the last step that ran, like Python schwab-api trade_v2().
+func (r TradeResult) Messages() []string {
+	msgs := r.VerifyMessages
+	if r.ExecMessages != nil {
+		msgs = r.ExecMessages
+	}
+	texts := []string{}
+	for _, m := range msgs {
+		texts = append(texts, m.Message)
+	}
+	return texts
+}
+
+// succeeded reports whether the last step that ran succeeded.
+func (r TradeResult) succeeded() bool {
+	if r.ExecMessages != nil {
+		return r.Placed
+	}
+	return r.Verified
+}
+
+// PlaceOrder verifies an order and, unless order.DryRun is set, executes it.
+// A rejected order returns the result with its messages and an *APIError matching ErrOrderRejected.
+func (c *Client) PlaceOrder(order OrderRequest) (TradeResult, error) {
+	return c.PlaceOrderContext(context.Background(), order)
+}
+
+// PlaceOrderContext is like PlaceOrder but uses ctx for cancellation and deadlines.
+func (c *Client) PlaceOrderContext(ctx context.Context, order OrderRequest) (TradeResult, error) {
+	if err := order.validate(); err != nil {
+		return TradeResult{}, err
+	}
+	return c.submitOrder(ctx, order.payload(), order.DryRun)
+}
+
+// submitOrder runs the verify-then-execute round trip against OrderVerificationV2Url:
+// OrderProcessingControl 1 verifies the payload, 2 executes it with the returned order ID.
+func (c *Client) submitOrder(ctx context.Context, requestBody map[string]interface{}, dryRun bool) (TradeResult, error) {
+	var result TradeResult
+	c.UpdateTokenContext(ctx, "update")
+
+	status, bodyBytes, err := c.postOrder(ctx, requestBody)
+	if err != nil {
+		return result, err
+	}
+	if c.Debug {
+		log.Printf("Verification Response: %s", string(bodyBytes))
+	}
+
+	if status != 200 {
+		return result, newAPIError("POST", c.endpoint(OrderVerificationV2Url), status, bodyBytes)
+	}
+
+	var verifyResp OrderVerificationResponse
+	if err := json.Unmarshal(bodyBytes, &verifyResp); err != nil {
+		return result, err
+	}
+
+	strategy := verifyResp.OrderStrategy
+	result.OrderID = strategy.OrderId
+	result.ReturnCode = strategy.OrderReturnCode
+	result.VerifyMessages = tradeMessages(verifyResp)
+	result.EstimatedCost = float64(strategy.EstimatedTotalAmount)
+	result.Commission = float64(strategy.Commission)
+	result.Fees = float64(strategy.Fees)
+	if !verificationPassed(result) {
+		return result, newOrderRejectedError(c.endpoint(OrderVerificationV2Url), strategy.OrderReturnCode, result.Messages(), bodyBytes)
+	}
+	result.Verified = true
+
+	if dryRun {
+		return result, nil
+	}
+
+	// Acknowledge warnings before executing, like Python's affirm flag
+	if warnings := messagesOfClass(result.VerifyMessages, MessageWarning); c.AckPolicy != nil && len(warnings) > 0 {
+		ok, err := c.AckPolicy.acknowledge(ctx, warnings)
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			return result, fmt.Errorf("%w: %s", ErrWarningsNotAcknowledged, strings.Join(TradeResult{VerifyMessages: warnings}.Messages(), "; "))
+		}
+		requestBody["OrderStrategy"].(map[string]interface{})["OrderAffrmIn"] = true
+		result.Acknowledged = true
+	}
+
+	// Proceed to execution
+	if legs, ok := requestBody["OrderStrategy"].(map[string]interface{})["OrderLegs"].([]map[string]interface{}); ok {
+		applyItemIssueIds(legs, strategy.OrderLegs)
+	}
+
+	// Update for execution
+	requestBody["UserContext"].(map[string]interface{})["CustomerId"] = 0
+	requestBody["OrderStrategy"].(map[string]interface{})["OrderId"] = strategy.OrderId
+	requestBody["OrderProcessingControl"] = 2 // Execution
+
+	c.UpdateTokenContext(ctx, "update")
+
+	status, execBytes, err := c.postOrder(ctx, requestBody)
+	if err != nil {
+		return result, err
+	}
+	if c.Debug {
+		log.Printf("Execution Response: %s", string(execBytes))
+	}
+	if status != 200 {
+		return result, newAPIError("POST", c.endpoint(OrderVerificationV2Url), status, execBytes)
+	}
+
+	// Re-using struct as response is similar
+	var execResp OrderVerificationResponse
+	if err := json.Unmarshal(execBytes, &execResp); err != nil {
+		return result, err
+	}
+
+	result.ReturnCode = execResp.OrderStrategy.OrderReturnCode
+	result.ExecMessages = tradeMessages(execResp)
+	if id := execResp.OrderStrategy.OrderId; id != 0 {
+		result.OrderID = id
+	}
+	if !validReturnCodes[result.ReturnCode] {
+		return result, newOrderRejectedError(c.endpoint(OrderVerificationV2Url), result.ReturnCode, result.Messages(), execBytes)
+	}
+	result.Placed = true
+	return result, nil
+}
+
+// applyItemIssueIds sets each request leg's Instrument.ItemIssueId to the SchwabSecurityId
+// of its verified leg, matching by symbol and falling back to position.
+func applyItemIssueIds(legs []map[string]interface{}, verified []OrderLeg) {
+	assigned := make([]bool, len(legs))
+	var unmatched []OrderLeg
+	for _, v := range verified {
+		matched := false
+		if v.Instrument.Symbol != "" {
+			for i, leg := range legs {
+				instrument := leg["Instrument"].(map[string]interface{})
+				if !assigned[i] && sameSymbol(instrument["Symbol"].(string), v.Instrument.Symbol) {
+					instrument["ItemIssueId"] = v.SchwabSecurityId
+					assigned[i], matched = true, true
+					break
+				}
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, v)
+		}
+	}
+	for i := range legs {
+		if assigned[i] || len(unmatched) == 0 {
+			continue
+		}
+		legs[i]["Instrument"].(map[string]interface{})["ItemIssueId"] = unmatched[0].SchwabSecurityId
+		unmatched = unmatched[1:]
+	}
+}
+
+// sameSymbol compares symbols ignoring case and the space padding of OCC option symbols.
+func sameSymbol(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), ""), strings.Join(strings.Fields(b), ""))
+}
+
+// validReturnCodes are the OrderReturnCode values treated as success (0, 10 are usually success/warning).
+var validReturnCodes = map[int]bool{0: true, 10: true}
+
+// tradeMessages converts and classifies the order messages of a verification or execution response.
+func tradeMessages(resp OrderVerificationResponse) []TradeMessage {
+	messages := []TradeMessage{}
+	for _, msg := range resp.OrderStrategy.OrderMessages {
+		messages = append(messages, TradeMessage{
+			Message:  msg.Message,
+			Severity: string(msg.Severity),
+			Class:    classifyOrderMessage(string(msg.Severity)),
+		})
+	}
+	return messages
+}
+
+// verificationPassed reports whether a verified order may go on to execution: the return code
+// must be 0 or 10 and no message may be an error. Warnings are then left to the AckPolicy, if any.
+func verificationPassed(result TradeResult) bool {
+	return validReturnCodes[result.ReturnCode] && len(messagesOfClass(result.VerifyMessages, MessageError)) == 0
+}
+
+// postOrder sends an order payload to OrderVerificationV2Url and returns the status code and body.
+func (c *Client) postOrder(ctx context.Context, requestBody map[string]interface{}) (int, []byte, error) {
+	return c.do(ctx, "POST", c.endpoint(OrderVerificationV2Url), requestBody, map[string]string{
+		"schwab-resource-version": "1.0",
+	})
+}
+
+// OrderFilter narrows the results of GetOrders. Zero values match everything.
+type OrderFilter struct {
+	// AccountID limits results to one account (sent as schwab-client-account).
+	AccountID string
+	// Status matches Order.Status case-insensitively, e.g. "Open", "Filled", "Cancelled".
+	Status string
+}
+
+// GetOrders lists open and historical orders from OrdersV2Url (Python schwab-api orders_v2()).
+// Multi-leg orders yield one Order per leg, all sharing the same OrderID.
+func (c *Client) GetOrders(filter OrderFilter) ([]Order, error) {
+	return c.GetOrdersContext(context.Background(), filter)
+}
+
+// GetOrdersContext is like GetOrders but uses ctx for cancellation and deadlines.
+func (c *Client) GetOrdersContext(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	if err := c.UpdateTokenContext(ctx, "api"); err != nil && c.Debug {
+		log.Printf("UpdateToken(api) warning: %v", err)
+	}
+
+	extra := map[string]string{"schwab-resource-version": "2.0"}
+	if filter.AccountID != "" {
+		extra["schwab-client-account"] = filter.AccountID
+	}
+	u := c.endpoint(OrdersV2Url)
+	status, body, err := c.do(ctx, "GET", u, nil, extra)
+	if err != nil {
+		return nil, err
+	}
+	if status != 200 {
+		return nil, newAPIError("GET", u, status, body)
+	}
+
+	var data OrdersV2Response
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	return ordersFromResponse(data, filter), nil
+}
+
+// ordersFromResponse flattens an OrdersV2Response into Orders matching filter.
+func ordersFromResponse(data OrdersV2Response, filter OrderFilter) []Order {
+	orders := []Order{}
+	for _, group := range data.Orders {
+		for _, detail := range group.OrderList {
+			if filter.AccountID != "" && detail.AccountNumber != "" && detail.AccountNumber != filter.AccountID {
+				continue
+			}
+			if filter.Status != "" && !strings.EqualFold(string(detail.Status), filter.Status) {
+				continue
+			}
+			base := Order{
+				OrderID:        detail.OrderId,
+				AccountID:      detail.AccountNumber,
+				LimitPrice:     float64(detail.LimitPrice),
+				StopPrice:      float64(detail.StopPrice),
+				Status:         string(detail.Status),
+				EnteredTime:    parseTime(detail.EnteredTime),
+				ExecutedTime:   parseTime(detail.ExecutedTime),
+				InstrumentType: InstrumentStock,
+			}
+			if len(detail.OrderLegs) == 0 {
+				orders = append(orders, base)
+				continue
+			}
+			for _, leg := range detail.OrderLegs {
+				o := base
+				o.Symbol = leg.Symbol
+				o.InstrumentType = instrumentTypeOf(int(leg.SecurityType), leg.Symbol)
+				o.Side = string(leg.Action)
+				o.Quantity = float64(leg.Quantity)
+				o.FilledQuantity = float64(leg.FilledQuantity)
+				orders = append(orders, o)
+			}
+		}
+	}
+	return orders
+}
@@ -0,0 +1,218 @@
+package schwab
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestOrderRequest_Payload(t *testing.T) {
+	tests := []struct {
+		name      string
+		order     OrderRequest
+		wantType  string
+		wantLimit string
+		wantStop  string
+	}{
+		{"market default", OrderRequest{}, "49", "0", "0"},
+		{"limit", OrderRequest{Type: OrderTypeLimit, LimitPrice: 150.25}, "50", "150.25", "0"},
+		{"stop", OrderRequest{Type: OrderTypeStop, StopPrice: 140}, "51", "0", "140"},
+		{"stop limit", OrderRequest{Type: OrderTypeStopLimit, LimitPrice: 139.5, StopPrice: 140}, "52", "139.5", "140"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := tt.order
+			o.AccountID, o.Symbol, o.Side, o.Quantity = "123", "AAPL", "Sell", 2
+			if err := o.validate(); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+			strategy := o.payload()["OrderStrategy"].(map[string]interface{})
+			if got := strategy["OrderType"]; got != tt.wantType {
+				t.Errorf("OrderType = %v, want %s", got, tt.wantType)
+			}
+			if got := strategy["LimitPrice"]; got != tt.wantLimit {
+				t.Errorf("LimitPrice = %v, want %s", got, tt.wantLimit)
+			}
+			if got := strategy["StopPrice"]; got != tt.wantStop {
+				t.Errorf("StopPrice = %v, want %s", got, tt.wantStop)
+			}
+			leg := strategy["OrderLegs"].([]map[string]interface{})[0]
+			if leg["Instruction"] != "50" {
+				t.Errorf("Instruction = %v, want 50 (Sell)", leg["Instruction"])
+			}
+		})
+	}
+}
+
+func TestOrderRequest_ValidatePrices(t *testing.T) {
+	base := OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Buy", Quantity: 1}
+	bad := []OrderRequest{
+		{Type: OrderTypeLimit},
+		{Type: OrderTypeStop},
+		{Type: OrderTypeStopLimit, LimitPrice: 10},
+		{Type: OrderTypeStopLimit, StopPrice: 10},
+		{Type: OrderTypeMarket, LimitPrice: 10},
+		{Type: "Trailing"},
+	}
+	for _, o := range bad {
+		o.AccountID, o.Symbol, o.Side, o.Quantity = base.AccountID, base.Symbol, base.Side, base.Quantity
+		if err := o.validate(); err == nil {
+			t.Errorf("validate(%+v) = nil, want error", o)
+		}
+	}
+}
+
+func TestPlaceOrder_InvalidQuantity(t *testing.T) {
+	c := NewClient(false)
+	result, err := c.PlaceOrder(OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Buy"})
+	if err == nil {
+		t.Fatal("expected error for zero quantity")
+	}
+	if result.Verified {
+		t.Error("expected unverified result for zero quantity")
+	}
+}
+
+func TestOrderRequest_DurationPayload(t *testing.T) {
+	types := []OrderType{OrderTypeMarket, OrderTypeLimit, OrderTypeStop, OrderTypeStopLimit}
+	durations := []struct {
+		d    Duration
+		code string
+	}{
+		{"", "48"},
+		{DurationDay, "48"},
+		{DurationGTC, "49"},
+		{DurationExtendedHours, "50"},
+		{DurationFillOrKill, "51"},
+		{DurationImmediateOrCancel, "52"},
+		{DurationDayPlusExtended, "201"},
+	}
+	// Only these combinations are accepted; everything else must fail validation.
+	valid := map[OrderType]map[Duration]bool{
+		OrderTypeMarket:    {"": true, DurationDay: true},
+		OrderTypeLimit:     {"": true, DurationDay: true, DurationGTC: true, DurationExtendedHours: true, DurationFillOrKill: true, DurationImmediateOrCancel: true, DurationDayPlusExtended: true},
+		OrderTypeStop:      {"": true, DurationDay: true, DurationGTC: true},
+		OrderTypeStopLimit: {"": true, DurationDay: true, DurationGTC: true},
+	}
+	for _, typ := range types {
+		for _, d := range durations {
+			o := OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Buy", Quantity: 1, Type: typ, Duration: d.d}
+			if typ == OrderTypeLimit || typ == OrderTypeStopLimit {
+				o.LimitPrice = 100
+			}
+			if typ == OrderTypeStop || typ == OrderTypeStopLimit {
+				o.StopPrice = 101
+			}
+			err := o.validate()
+			if !valid[typ][d.d] {
+				if err == nil {
+					t.Errorf("%s/%s: expected validation error", typ, d.d)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%s/%s: unexpected error: %v", typ, d.d, err)
+				continue
+			}
+			strategy := o.payload()["OrderStrategy"].(map[string]interface{})
+			if got := strategy["Duration"]; got != d.code {
+				t.Errorf("%s/%s: Duration = %v, want %s", typ, d.d, got, d.code)
+			}
+			if got := strategy["OrderType"]; got != orderTypeCodes[typ] {
+				t.Errorf("%s/%s: OrderType = %v, want %s", typ, d.d, got, orderTypeCodes[typ])
+			}
+		}
+	}
+}
+
+func TestOrderRequest_UnknownDuration(t *testing.T) {
+	o := OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Buy", Quantity: 1, Type: OrderTypeLimit, LimitPrice: 1, Duration: "Week"}
+	if err := o.validate(); err == nil {
+		t.Fatal("expected error for unknown duration")
+	}
+}
+
+func TestGetOrders(t *testing.T) {
+	var gotAccount string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/is.TradeOrderStatusWeb/ITradeOrderStatusWeb/ITradeOrderStatusWebPort/orders/listView" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		gotAccount = r.Header.Get("schwab-client-account")
+		w.Write([]byte(`{"Orders":[{"OrderList":[
+			{"OrderId":101,"AccountNumber":"123","DisplayStatus":"Open","LimitPrice":"150.25","StopPrice":0,
+			 "EnteredTime":"2024-03-01T09:31:00","OrderLegs":[{"Symbol":"AAPL","Action":"Buy","Quantity":"10","FilledQuantity":"4"}]},
+			{"OrderId":102,"AccountNumber":"123","DisplayStatus":"Filled","EnteredTime":"2024-03-01T09:32:00",
+			 "ExecutedTime":"2024-03-01T09:32:05","OrderLegs":[{"Symbol":"MSFT","Action":"Sell","Quantity":5,"FilledQuantity":5}]}
+		]}]}`))
+	})
+
+	orders, err := c.GetOrders(OrderFilter{AccountID: "123", Status: "open"})
+	if err != nil {
+		t.Fatalf("GetOrders: %v", err)
+	}
+	if gotAccount != "123" {
+		t.Errorf("schwab-client-account = %q, want 123", gotAccount)
+	}
+	if len(orders) != 1 {
+		t.Fatalf("got %d orders, want 1", len(orders))
+	}
+	o := orders[0]
+	if o.OrderID != 101 || o.Symbol != "AAPL" || o.Side != "Buy" || o.Quantity != 10 || o.FilledQuantity != 4 || o.LimitPrice != 150.25 {
+		t.Errorf("unexpected order: %+v", o)
+	}
+	if o.EnteredTime.IsZero() {
+		t.Error("EnteredTime not parsed")
+	}
+	if !o.ExecutedTime.IsZero() {
+		t.Errorf("ExecutedTime = %v, want zero", o.ExecutedTime)
+	}
+
+	all, err := c.GetOrders(OrderFilter{})
+	if err != nil {
+		t.Fatalf("GetOrders: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("got %d orders, want 2", len(all))
+	}
+}
+
+func TestPlaceOrder_Result(t *testing.T) {
+	step := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		step++
+		if step == 1 {
+			w.Write([]byte(`{"orderStrategy":{"orderId":555,"orderReturnCode":10,
+				"estimatedTotalAmount":"$1,502.35","commission":0,"fees":"0.02",
+				"orderLegs":[{"schwabSecurityId":1}],
+				"orderMessages":[{"message":"Large order","severity":10}]}}`))
+			return
+		}
+		w.Write([]byte(`{"orderStrategy":{"orderId":555,"orderReturnCode":0,"orderMessages":[{"message":"Order placed","severity":"Info"}]}}`))
+	})
+
+	result, err := c.PlaceOrder(OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Buy", Quantity: 10})
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if !result.Verified || !result.Placed || result.OrderID != 555 || result.ReturnCode != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.EstimatedCost != 1502.35 || result.Commission != 0 || result.Fees != 0.02 {
+		t.Errorf("estimates = %v, %v, %v", result.EstimatedCost, result.Commission, result.Fees)
+	}
+	if len(result.VerifyMessages) != 1 || result.VerifyMessages[0] != (TradeMessage{Message: "Large order", Severity: "10", Class: MessageWarning}) {
+		t.Errorf("VerifyMessages = %+v", result.VerifyMessages)
+	}
+	if got := result.Messages(); len(got) != 1 || got[0] != "Order placed" {
+		t.Errorf("Messages() = %v, want execution messages", got)
+	}
+
+	step = 0
+	result, err = c.PlaceOrder(OrderRequest{AccountID: "123", Symbol: "AAPL", Side: "Buy", Quantity: 10, DryRun: true})
+	if err != nil || !result.Verified || result.Placed || result.ExecMessages != nil || step != 1 {
+		t.Errorf("dry run: result=%+v err=%v steps=%d", result, err, step)
+	}
+	if got := result.Messages(); len(got) != 1 || got[0] != "Large order" {
+		t.Errorf("dry run Messages() = %v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package schwab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// maxQuoteSymbols is the largest number of symbols sent in one TickerQuotesV2Url request.
+const maxQuoteSymbols = 50
+
+// GetQuotes returns quotes for symbols via TickerQuotesV2Url (Python schwab-api quote_v2()).
+// Symbols are split into batches of maxQuoteSymbols; results keep the order Schwab returns them in.
+func (c *Client) GetQuotes(symbols ...string) ([]Quote, error) {
+	return c.GetQuotesContext(context.Background(), symbols...)
+}
+
+// GetQuotesContext is like GetQuotes but uses ctx for cancellation and deadlines.
+func (c *Client) GetQuotesContext(ctx context.Context, symbols ...string) ([]Quote, error) {
+	var clean []string
+	for _, s := range symbols {
+		if s = strings.TrimSpace(s); s != "" {
+			clean = append(clean, s)
+		}
+	}
+	if len(clean) == 0 {
+		return nil, fmt.Errorf("at least one symbol is required")
+	}
+
+	if err := c.UpdateTokenContext(ctx, "update"); err != nil && c.Debug {
+		log.Printf("UpdateToken(update) warning: %v", err)
+	}
+
+	quotes := []Quote{}
+	for start := 0; start < len(clean); start += maxQuoteSymbols {
+		end := min(start+maxQuoteSymbols, len(clean))
+		batch, err := c.getQuoteBatch(ctx, clean[start:end])
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, batch...)
+	}
+	return quotes, nil
+}
+
+// getQuoteBatch posts one batch of symbols, with the same body as Python quote_v2().
+func (c *Client) getQuoteBatch(ctx context.Context, symbols []string) ([]Quote, error) {
+	u := c.endpoint(TickerQuotesV2Url)
+	requestBody := map[string]interface{}{
+		"Symbols":        symbols,
+		"IsIra":          false,
+		"AccountRegType": "S3",
+	}
+	status, body, err := c.do(ctx, "POST", u, requestBody, map[string]string{
+		"schwab-resource-version": "1.0",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status != 200 {
+		return nil, newAPIError("POST", u, status, body)
+	}
+
+	var data QuotesV2Response
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	quotes := make([]Quote, 0, len(data.Quotes))
+	for _, q := range data.Quotes {
+		quotes = append(quotes, Quote{
+			Symbol:        q.Symbol,
+			Last:          float64(q.Quote.Last),
+			Bid:           float64(q.Quote.Bid),
+			Ask:           float64(q.Quote.Ask),
+			BidSize:       float64(q.Quote.BidSize),
+			AskSize:       float64(q.Quote.AskSize),
+			Volume:        float64(q.Quote.Volume),
+			NetChange:     float64(q.Quote.NetChange),
+			PercentChange: float64(q.Quote.PercentChange),
+			High52Week:    float64(q.Quote.High52),
+			Low52Week:     float64(q.Quote.Low52),
+			QuoteTime:     parseTime(q.Quote.QuoteTime),
+		})
+	}
+	return quotes, nil
+}
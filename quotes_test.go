@@ -0,0 +1,63 @@
+package schwab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetQuotes_Batches(t *testing.T) {
+	var batches [][]string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Symbols        []string
+			IsIra          bool
+			AccountRegType string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		if r.Method != "POST" || r.Header.Get("schwab-resource-version") != "1.0" || req.IsIra || req.AccountRegType != "S3" {
+			t.Errorf("unexpected request: %s %+v, schwab-resource-version %q", r.Method, req, r.Header.Get("schwab-resource-version"))
+		}
+		symbols := req.Symbols
+		batches = append(batches, symbols)
+		var parts []string
+		for _, s := range symbols {
+			parts = append(parts, fmt.Sprintf(`{"symbol":%q,"quote":{"last":"1,234.5","bid":1234.4,"ask":1234.6,"bidSize":100,"askSize":200,"volume":"1000000","netChange":-1.5,"percentChange":-0.12,"high52":1300,"low52":900,"quoteTime":"2024-03-01T16:00:00Z"}}`, s))
+		}
+		w.Write([]byte(`{"quotes":[` + strings.Join(parts, ",") + `]}`))
+	})
+
+	symbols := make([]string, maxQuoteSymbols+5)
+	for i := range symbols {
+		symbols[i] = fmt.Sprintf("S%d", i)
+	}
+	quotes, err := c.GetQuotes(symbols...)
+	if err != nil {
+		t.Fatalf("GetQuotes: %v", err)
+	}
+	if len(batches) != 2 || len(batches[0]) != maxQuoteSymbols || len(batches[1]) != 5 {
+		t.Fatalf("unexpected batching: %d batches", len(batches))
+	}
+	if batches[0][0] != "S0" || batches[0][maxQuoteSymbols-1] != fmt.Sprintf("S%d", maxQuoteSymbols-1) ||
+		batches[1][0] != fmt.Sprintf("S%d", maxQuoteSymbols) || batches[1][4] != fmt.Sprintf("S%d", maxQuoteSymbols+4) {
+		t.Errorf("unexpected batch symbols: %v", batches)
+	}
+	if len(quotes) != len(symbols) {
+		t.Fatalf("got %d quotes, want %d", len(quotes), len(symbols))
+	}
+	q := quotes[0]
+	if q.Symbol != "S0" || q.Last != 1234.5 || q.Volume != 1000000 || q.NetChange != -1.5 || q.High52Week != 1300 || q.QuoteTime.IsZero() {
+		t.Errorf("unexpected quote: %+v", q)
+	}
+}
+
+func TestGetQuotes_NoSymbols(t *testing.T) {
+	c := NewClient(false)
+	if _, err := c.GetQuotes(" ", ""); err == nil {
+		t.Fatal("expected error with no symbols")
+	}
+}
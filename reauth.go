@@ -0,0 +1,65 @@
+package schwab
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// ReauthPolicy enables automatic re-authentication. When a request or token refresh is rejected
+// with 401/403, the client restores Store's session (if set and still valid) or runs Login
+// with the stored credentials, then retries the original request once.
+// If Authenticator is set it is used instead of the credentials and Store.
+type ReauthPolicy struct {
+	Username   string
+	Password   string
+	TOTPSecret string
+	// Store, if set, is tried before a browser login and receives the new session after one.
+	Store SessionStore
+	// Authenticator, if set, supplies the new session instead of a browser login.
+	Authenticator Authenticator
+}
+
+// needsReauth reports whether status means the session is no longer accepted.
+func needsReauth(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
+
+// reauthKey marks the context of a re-authentication so requests made by it don't recurse.
+type reauthKey struct{}
+
+// canReauth reports whether a failed request may trigger re-authentication.
+func (c *Client) canReauth(ctx context.Context) bool {
+	return c.Reauth != nil && ctx.Value(reauthKey{}) == nil
+}
+
+// reauthenticate re-establishes the session according to c.Reauth. gen is the session
+// generation the failed request used: if another goroutine has already replaced that
+// session, it returns without logging in again and the caller retries with the new one.
+func (c *Client) reauthenticate(ctx context.Context, gen uint64) error {
+	p := c.Reauth
+	c.reauthMu.Lock()
+	defer c.reauthMu.Unlock()
+	if c.generation() != gen {
+		return nil
+	}
+	ctx = context.WithValue(ctx, reauthKey{}, true)
+
+	if c.Debug {
+		log.Println("Session rejected; re-authenticating...")
+	}
+	var err error
+	switch {
+	case p.Authenticator != nil:
+		err = c.AuthenticateContext(ctx, p.Authenticator)
+	case p.Store != nil:
+		err = c.LoginOrRestoreFromContext(ctx, p.Store, p.Username, p.Password, p.TOTPSecret)
+	default:
+		err = c.LoginContext(ctx, p.Username, p.Password, p.TOTPSecret)
+	}
+	if err != nil {
+		return fmt.Errorf("re-authentication failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package schwab
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestReauth_RetriesAfterUnauthorized(t *testing.T) {
+	holdingsCalls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		holdingsCalls++
+		if r.Header.Get("Cookie") != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"accounts":[{"accountId":"123"}]}`))
+	})
+	c.RestoreSession(Session{BearerToken: "Bearer stale", Cookies: "stale"})
+	store := &MemorySessionStore{}
+	store.Save(Session{BearerToken: "Bearer fresh", Cookies: "fresh"})
+	c.Reauth = &ReauthPolicy{Username: "user", Password: "pass", Store: store}
+
+	accounts, err := c.GetAccountInfo()
+	if err != nil {
+		t.Fatalf("GetAccountInfo: %v", err)
+	}
+	if len(accounts) != 1 || holdingsCalls != 2 {
+		t.Errorf("got %d accounts after %d calls, want 1 after 2", len(accounts), holdingsCalls)
+	}
+}
+
+func TestReauth_DisabledByDefault(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusForbidden)
+	})
+	if _, err := c.GetAccountInfo(); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1 (no retry without Reauth)", calls)
+	}
+}
+
+func TestReauth_TokenRefreshFailure(t *testing.T) {
+	c := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/auth/authorize/scope/") {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			return
+		}
+		if r.Header.Get("Cookie") != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"token":"new"}`))
+	})
+	c.RestoreSession(Session{BearerToken: "Bearer stale", Cookies: "stale"})
+	store := &MemorySessionStore{}
+	store.Save(Session{BearerToken: "Bearer fresh", Cookies: "fresh"})
+	c.Reauth = &ReauthPolicy{Store: store}
+
+	if err := c.UpdateToken("api"); err != nil {
+		t.Fatalf("UpdateToken: %v", err)
+	}
+	if c.BearerToken != "Bearer new" {
+		t.Errorf("BearerToken = %q, want Bearer new", c.BearerToken)
+	}
+}
+
+// countingAuthenticator returns session and counts its calls.
+type countingAuthenticator struct {
+	session Session
+	calls   int
+}
+
+func (a *countingAuthenticator) Authenticate(ctx context.Context) (Session, error) {
+	a.calls++
+	return a.session, nil
+}
+
+func TestReauth_LoginOrRestoreSavesNewSession(t *testing.T) {
+	c := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cookie") != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"token":"new"}`))
+	})
+	store := &MemorySessionStore{}
+	store.Save(Session{BearerToken: "Bearer stale", Cookies: "stale"})
+	reauth := &countingAuthenticator{session: Session{BearerToken: "Bearer reauth", Cookies: "fresh"}}
+	c.Reauth = &ReauthPolicy{Authenticator: reauth}
+	login := &countingAuthenticator{session: Session{BearerToken: "Bearer fresh", Cookies: "fresh"}}
+
+	if err := c.restoreOrAuthenticate(context.Background(), store, login); err != nil {
+		t.Fatalf("restoreOrAuthenticate: %v", err)
+	}
+	if reauth.calls != 0 || login.calls != 1 {
+		t.Errorf("reauth called %d times and login %d times, want 0 and 1", reauth.calls, login.calls)
+	}
+	saved, err := store.Load()
+	if err != nil || saved.Cookies != "fresh" {
+		t.Errorf("store holds %+v (err %v), want the new session", saved, err)
+	}
+}
+
+func TestReauth_TokenRefreshNotRejected(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		body   string
+	}{
+		{http.StatusTooManyRequests, ""},
+		{http.StatusServiceUnavailable, ""},
+		{http.StatusOK, "<html>"},
+	} {
+		c := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		})
+		c.RestoreSession(Session{BearerToken: "Bearer old", Cookies: "a=1"})
+		reauth := &countingAuthenticator{session: Session{BearerToken: "Bearer new", Cookies: "b=2"}}
+		c.Reauth = &ReauthPolicy{Authenticator: reauth}
+
+		if err := c.UpdateToken("api"); err == nil {
+			t.Errorf("status %d: expected error", tt.status)
+		}
+		if reauth.calls != 0 {
+			t.Errorf("status %d: re-authenticated %d times, want 0", tt.status, reauth.calls)
+		}
+	}
+}
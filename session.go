@@ -0,0 +1,143 @@
+package schwab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Session is the state captured by Login: request headers, bearer token and cookies.
+type Session struct {
+	Headers     map[string]string `json:"headers"`
+	BearerToken string            `json:"bearer_token"`
+	Cookies     string            `json:"cookies"`
+	CapturedAt  time.Time         `json:"captured_at"`
+}
+
+// Session returns a copy of the client's current session.
+func (c *Client) Session() Session {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	headers := make(map[string]string, len(c.Headers))
+	for k, v := range c.Headers {
+		if !isSessionHeader(k) {
+			headers[k] = v
+		}
+	}
+	return Session{
+		Headers:     headers,
+		BearerToken: c.BearerToken,
+		Cookies:     c.Headers["Cookie"],
+		CapturedAt:  c.capturedAt,
+	}
+}
+
+// RestoreSession replaces the client's session with s.
+// Authorization and Cookie entries in s.Headers are ignored in favour of s.BearerToken and s.Cookies.
+func (c *Client) RestoreSession(s Session) {
+	headers := make(map[string]string, len(s.Headers)+2)
+	for k, v := range s.Headers {
+		if !isSessionHeader(k) {
+			headers[k] = v
+		}
+	}
+	if s.BearerToken != "" {
+		headers["Authorization"] = s.BearerToken
+	}
+	if s.Cookies != "" {
+		headers["Cookie"] = s.Cookies
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Headers = headers
+	c.BearerToken = s.BearerToken
+	c.capturedAt = s.CapturedAt
+	c.sessionGen++
+}
+
+// SaveSession writes the current session to path as JSON, readable only by the owner.
+func (c *Client) SaveSession(path string) error {
+	return c.SaveSessionTo(FileSessionStore{Path: path})
+}
+
+// LoadSession restores a session written by SaveSession. It does not check that the session is still valid.
+func (c *Client) LoadSession(path string) error {
+	return c.LoadSessionFrom(FileSessionStore{Path: path})
+}
+
+// LoginOrRestore loads the session at path and checks it with UpdateToken("api").
+// If there is no saved session or it has expired, it performs a browser Login and saves the new session to path.
+// Either way, accounts are discovered when c.DiscoverAccounts is set, as with Login.
+func (c *Client) LoginOrRestore(path, username, password, totpSecret string) error {
+	return c.LoginOrRestoreContext(context.Background(), path, username, password, totpSecret)
+}
+
+// LoginOrRestoreContext is like LoginOrRestore but uses ctx for cancellation and deadlines.
+func (c *Client) LoginOrRestoreContext(ctx context.Context, path, username, password, totpSecret string) error {
+	return c.LoginOrRestoreFromContext(ctx, FileSessionStore{Path: path}, username, password, totpSecret)
+}
+
+// SaveSessionTo saves the current session in store.
+func (c *Client) SaveSessionTo(store SessionStore) error {
+	s := c.Session()
+	if s.BearerToken == "" {
+		return fmt.Errorf("no session to save; call Login first")
+	}
+	return store.Save(s)
+}
+
+// LoadSessionFrom restores the session saved in store. It does not check that the session is still valid.
+func (c *Client) LoadSessionFrom(store SessionStore) error {
+	s, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if s.BearerToken == "" {
+		return fmt.Errorf("saved session has no bearer token")
+	}
+	c.RestoreSession(s)
+	return nil
+}
+
+// LoginOrRestoreFrom is LoginOrRestore for any SessionStore, such as an EncryptedFileSessionStore.
+func (c *Client) LoginOrRestoreFrom(store SessionStore, username, password, totpSecret string) error {
+	return c.LoginOrRestoreFromContext(context.Background(), store, username, password, totpSecret)
+}
+
+// LoginOrRestoreFromContext is like LoginOrRestoreFrom but uses ctx for cancellation and deadlines.
+func (c *Client) LoginOrRestoreFromContext(ctx context.Context, store SessionStore, username, password, totpSecret string) error {
+	return c.restoreOrAuthenticate(ctx, store, c.playwrightAuthenticator(username, password, totpSecret))
+}
+
+// restoreOrAuthenticate restores the session in store if it is still valid, and otherwise
+// authenticates with login and saves the new session to store.
+func (c *Client) restoreOrAuthenticate(ctx context.Context, store SessionStore, login Authenticator) error {
+	if err := c.LoadSessionFrom(store); err == nil {
+		// updateToken, not UpdateTokenContext: with c.Reauth set, a rejected session would be
+		// replaced behind our back and the new one never saved to store.
+		if _, err := c.updateToken(ctx, "api"); err == nil {
+			if c.Debug {
+				log.Printf("Restored session (captured %s)", c.Session().CapturedAt.Format(time.RFC3339))
+			}
+			if c.DiscoverAccounts && len(c.accountIDs()) == 0 {
+				return c.discoverAccounts(ctx)
+			}
+			return nil
+		} else if c.Debug {
+			log.Printf("Saved session is no longer valid: %v", err)
+		}
+	} else if c.Debug && !errors.Is(err, ErrNoSession) {
+		log.Printf("Could not load session: %v", err)
+	}
+
+	if err := c.AuthenticateContext(ctx, login); err != nil {
+		return err
+	}
+	if err := c.SaveSessionTo(store); err != nil {
+		return fmt.Errorf("logged in but failed to save session: %w", err)
+	}
+	return nil
+}
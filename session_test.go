@@ -0,0 +1,134 @@
+package schwab
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveLoadSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	c := NewClient(false)
+	if err := c.SaveSession(path); err == nil {
+		t.Fatal("expected error saving an empty session")
+	}
+
+	captured := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	c.RestoreSession(Session{
+		Headers:     map[string]string{"schwab-channelcode": "IO"},
+		BearerToken: "Bearer abc",
+		Cookies:     "a=1; b=2",
+		CapturedAt:  captured,
+	})
+	if err := c.SaveSession(path); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("session file mode = %o, want 600", perm)
+	}
+
+	c2 := NewClient(false)
+	if err := c2.LoadSession(path); err != nil {
+		t.Fatalf("LoadSession: %v", err)
+	}
+	if c2.BearerToken != "Bearer abc" || c2.Headers["Authorization"] != "Bearer abc" ||
+		c2.Headers["Cookie"] != "a=1; b=2" || c2.Headers["schwab-channelcode"] != "IO" {
+		t.Errorf("unexpected restored headers: %v", c2.Headers)
+	}
+	if s := c2.Session(); !s.CapturedAt.Equal(captured) || s.Cookies != "a=1; b=2" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+}
+
+func TestLoginOrRestore_ValidSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	saved := NewClient(false)
+	saved.RestoreSession(Session{BearerToken: "Bearer old", Cookies: "a=1"})
+	if err := saved.SaveSession(path); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotCookie string
+	c := newTestServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotCookie = r.Header.Get("Cookie")
+		w.Write([]byte(`{"token":"test-token"}`))
+	})
+	// Login would start a browser; a valid restored session must not reach it.
+	if err := c.LoginOrRestore(path, "user", "pass", ""); err != nil {
+		t.Fatalf("LoginOrRestore: %v", err)
+	}
+	if gotCookie != "a=1" {
+		t.Errorf("token refresh sent Cookie %q, want restored cookies", gotCookie)
+	}
+	if c.BearerToken != "Bearer test-token" {
+		t.Errorf("BearerToken = %q, want refreshed token", c.BearerToken)
+	}
+}
+
+func TestLoginOrRestore_DiscoversAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	saved := NewClient(false)
+	saved.RestoreSession(Session{BearerToken: "Bearer old", Cookies: "a=1"})
+	if err := saved.SaveSession(path); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Accounts":[{"AccountId":"11112222"},{"AccountId":"33334444"}]}`))
+	})
+	c.DiscoverAccounts = true
+	if err := c.LoginOrRestore(path, "user", "pass", ""); err != nil {
+		t.Fatalf("LoginOrRestore: %v", err)
+	}
+	if ids := c.accountIDs(); len(ids) != 2 || ids[0] != "11112222" || ids[1] != "33334444" {
+		t.Errorf("AccountIDs = %v, want discovered accounts", ids)
+	}
+}
+
+func TestRestoreSession_LowercaseHeaders(t *testing.T) {
+	var badAuth, badCookie int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			badAuth++
+		}
+		if got := r.Header.Get("Cookie"); got != "a=1" {
+			badCookie++
+		}
+		w.Write([]byte(`{"Accounts":[]}`))
+	})
+	// As captured by the browser: lowercase keys and the unfiltered cookie header.
+	c.RestoreSession(Session{
+		Headers: map[string]string{
+			"authorization":      "Bearer stale",
+			"cookie":             "a=1; tracking=x",
+			"schwab-channelcode": "IO",
+		},
+		BearerToken: "Bearer stale",
+		Cookies:     "a=1",
+	})
+
+	for i := 0; i < 50; i++ {
+		if _, err := c.ListAccounts(); err != nil {
+			t.Fatalf("ListAccounts: %v", err)
+		}
+	}
+	if badAuth > 0 || badCookie > 0 {
+		t.Errorf("%d request(s) sent a stale token and %d the unfiltered cookie", badAuth, badCookie)
+	}
+
+	s := c.Session()
+	for k := range s.Headers {
+		if isSessionHeader(k) {
+			t.Errorf("Session().Headers contains %q", k)
+		}
+	}
+	if s.Headers["schwab-channelcode"] != "IO" || s.BearerToken != "Bearer test-token" || s.Cookies != "a=1" {
+		t.Errorf("unexpected session: %+v", s)
+	}
+}
@@ -0,0 +1,252 @@
+package schwab
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// ErrNoSession is returned by SessionStore.Load when nothing has been saved yet.
+var ErrNoSession = errors.New("no saved session")
+
+// SessionStore persists a Session between runs.
+type SessionStore interface {
+	Save(Session) error
+	Load() (Session, error)
+}
+
+// FileSessionStore keeps the session as plain JSON at Path. The file is written with mode 0600
+// and Load refuses files that other users can access.
+type FileSessionStore struct {
+	Path string
+}
+
+func (s FileSessionStore) Save(session Session) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(s.Path, data)
+}
+
+func (s FileSessionStore) Load() (Session, error) {
+	var session Session
+	data, err := readSessionFile(s.Path)
+	if err != nil {
+		return session, err
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return session, fmt.Errorf("parse session %s: %w", s.Path, err)
+	}
+	return session, nil
+}
+
+// pbkdf2Iterations is the PBKDF2-SHA256 work factor for passphrase-derived keys.
+const pbkdf2Iterations = 600000
+
+// EncryptedFileSessionStore keeps the session encrypted with AES-256-GCM at Path.
+// The key is derived from a passphrase (PBKDF2-SHA256) or a key file (HKDF-SHA256)
+// with a fresh random salt on every Save. Create one with NewPassphraseSessionStore or NewKeyFileSessionStore.
+type EncryptedFileSessionStore struct {
+	Path       string
+	passphrase string
+	keyFile    string
+}
+
+// NewPassphraseSessionStore returns a store encrypting the session at path with a key derived from passphrase.
+func NewPassphraseSessionStore(path, passphrase string) *EncryptedFileSessionStore {
+	return &EncryptedFileSessionStore{Path: path, passphrase: passphrase}
+}
+
+// NewKeyFileSessionStore returns a store encrypting the session at path with a key derived from
+// the contents of keyFile, which must hold at least 32 bytes and must not be accessible by other users.
+func NewKeyFileSessionStore(path, keyFile string) *EncryptedFileSessionStore {
+	return &EncryptedFileSessionStore{Path: path, keyFile: keyFile}
+}
+
+// encryptedSession is the on-disk format of EncryptedFileSessionStore.
+type encryptedSession struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations,omitempty"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (s *EncryptedFileSessionStore) Save(session Session) error {
+	plaintext, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+	env := encryptedSession{Version: 1, Salt: make([]byte, 16)}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return err
+	}
+	key, err := s.deriveKey(&env)
+	if err != nil {
+		return err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	env.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return err
+	}
+	env.Ciphertext = aead.Seal(nil, env.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivateFile(s.Path, data)
+}
+
+func (s *EncryptedFileSessionStore) Load() (Session, error) {
+	var session Session
+	data, err := readSessionFile(s.Path)
+	if err != nil {
+		return session, err
+	}
+	var env encryptedSession
+	if err := json.Unmarshal(data, &env); err != nil {
+		return session, fmt.Errorf("parse session %s: %w", s.Path, err)
+	}
+	if env.Version != 1 {
+		return session, fmt.Errorf("session %s: unsupported version %d", s.Path, env.Version)
+	}
+	key, err := s.deriveKey(&env)
+	if err != nil {
+		return session, err
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return session, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return session, fmt.Errorf("session %s: invalid nonce", s.Path)
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return session, fmt.Errorf("decrypt session %s: wrong key or corrupted file", s.Path)
+	}
+	if err := json.Unmarshal(plaintext, &session); err != nil {
+		return session, fmt.Errorf("parse session %s: %w", s.Path, err)
+	}
+	return session, nil
+}
+
+// deriveKey derives the AES key for env. On Save it also records the KDF parameters in env;
+// on Load it checks them against the store's configuration.
+func (s *EncryptedFileSessionStore) deriveKey(env *encryptedSession) ([]byte, error) {
+	switch {
+	case s.keyFile != "":
+		if env.KDF != "" && env.KDF != "hkdf-sha256" {
+			return nil, fmt.Errorf("session %s was not encrypted with a key file", s.Path)
+		}
+		env.KDF = "hkdf-sha256"
+		secret, err := readPrivateFile(s.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read key file: %w", err)
+		}
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("key file %s must hold at least 32 bytes", s.keyFile)
+		}
+		return hkdf.Key(sha256.New, secret, env.Salt, "go-schwab session", 32)
+	case s.passphrase != "":
+		if env.KDF != "" && env.KDF != "pbkdf2-sha256" {
+			return nil, fmt.Errorf("session %s was not encrypted with a passphrase", s.Path)
+		}
+		env.KDF = "pbkdf2-sha256"
+		if env.Iterations == 0 {
+			env.Iterations = pbkdf2Iterations
+		}
+		return pbkdf2.Key(sha256.New, s.passphrase, env.Salt, env.Iterations, 32)
+	}
+	return nil, fmt.Errorf("encrypted session store needs a passphrase or key file")
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// MemorySessionStore keeps the session in memory; useful for tests.
+type MemorySessionStore struct {
+	mu      sync.Mutex
+	session *Session
+}
+
+func (s *MemorySessionStore) Save(session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session = &session
+	return nil
+}
+
+func (s *MemorySessionStore) Load() (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session == nil {
+		return Session{}, ErrNoSession
+	}
+	return *s.session, nil
+}
+
+// writePrivateFile atomically replaces path with data, readable only by the owner.
+func writePrivateFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil && runtime.GOOS != "windows" {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readSessionFile reads a session file with readPrivateFile, reporting a missing file as ErrNoSession.
+func readSessionFile(path string) ([]byte, error) {
+	data, err := readPrivateFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s does not exist", ErrNoSession, path)
+	}
+	return data, err
+}
+
+// readPrivateFile reads path, refusing files that other users can access.
+func readPrivateFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("refusing to read %s: mode %04o is accessible by other users (chmod 600)", path, info.Mode().Perm())
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,108 @@
+package schwab
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testSession() Session {
+	return Session{
+		Headers:     map[string]string{"schwab-channelcode": "IO"},
+		BearerToken: "Bearer secret-token",
+		Cookies:     "session=secret-cookie",
+	}
+}
+
+func TestEncryptedFileSessionStore_Passphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.enc")
+	store := NewPassphraseSessionStore(path, "correct horse")
+	if err := store.Save(testSession()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte("secret-token")) || bytes.Contains(data, []byte("secret-cookie")) {
+		t.Fatal("session file contains plaintext secrets")
+	}
+
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.BearerToken != "Bearer secret-token" || got.Cookies != "session=secret-cookie" {
+		t.Errorf("unexpected session: %+v", got)
+	}
+
+	if _, err := NewPassphraseSessionStore(path, "wrong").Load(); err == nil {
+		t.Error("expected error with wrong passphrase")
+	}
+}
+
+func TestEncryptedFileSessionStore_KeyFile(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "session.key")
+	if err := os.WriteFile(keyFile, bytes.Repeat([]byte{7}, 32), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "session.enc")
+	store := NewKeyFileSessionStore(path, keyFile)
+	if err := store.Save(testSession()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := store.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, err := NewPassphraseSessionStore(path, "x").Load(); err == nil {
+		t.Error("expected error loading a key-file session with a passphrase")
+	}
+
+	os.WriteFile(keyFile, []byte("short"), 0600)
+	if err := store.Save(testSession()); err == nil {
+		t.Error("expected error with a short key file")
+	}
+}
+
+func TestFileSessionStore_RefusesWorldReadable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	store := FileSessionStore{Path: path}
+	if _, err := store.Load(); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Load of missing file = %v, want ErrNoSession", err)
+	}
+	if err := store.Save(testSession()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	for _, mode := range []os.FileMode{0644, 0640, 0660} {
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.Load(); err == nil {
+			t.Fatalf("expected error loading a session file with mode %04o", mode)
+		}
+	}
+	// Saving again replaces the file with a private one.
+	if err := store.Save(testSession()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if _, err := store.Load(); err != nil {
+		t.Errorf("Load after re-save: %v", err)
+	}
+}
+
+func TestLoginOrRestoreFrom_MemoryStore(t *testing.T) {
+	store := &MemorySessionStore{}
+	if _, err := store.Load(); !errors.Is(err, ErrNoSession) {
+		t.Errorf("empty store Load = %v, want ErrNoSession", err)
+	}
+	store.Save(testSession())
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	if err := c.LoginOrRestoreFrom(store, "user", "pass", ""); err != nil {
+		t.Fatalf("LoginOrRestoreFrom: %v", err)
+	}
+	if c.Headers["Cookie"] != "session=secret-cookie" || c.BearerToken != "Bearer test-token" {
+		t.Errorf("unexpected client state: %q %v", c.BearerToken, c.Headers)
+	}
+}
@@ -0,0 +1,161 @@
+package schwab
+
+import (
+	"context"
+	"fmt"
+)
+
+// SpreadStrategy is the OrderStrategyType Schwab expects for a multi-leg option order
+// (codes as in Python schwab-api option_trade_v2()).
+type SpreadStrategy int
+
+const (
+	SpreadVerticalCall  SpreadStrategy = 201
+	SpreadVerticalPut   SpreadStrategy = 202
+	SpreadCalendarCall  SpreadStrategy = 203
+	SpreadCalendarPut   SpreadStrategy = 204
+	SpreadDiagonalCall  SpreadStrategy = 205
+	SpreadDiagonalPut   SpreadStrategy = 206
+	SpreadRatioCall     SpreadStrategy = 207
+	SpreadRatioPut      SpreadStrategy = 208
+	SpreadButterflyCall SpreadStrategy = 209
+	SpreadButterflyPut  SpreadStrategy = 210
+	SpreadCondorCall    SpreadStrategy = 211
+	SpreadCondorPut     SpreadStrategy = 212
+	SpreadIronCondor    SpreadStrategy = 214
+	SpreadStraddle      SpreadStrategy = 215
+	SpreadStrangle      SpreadStrategy = 216
+	SpreadCustom2Legs   SpreadStrategy = 217
+	SpreadCustom3Legs   SpreadStrategy = 218
+	SpreadCustom4Legs   SpreadStrategy = 219
+)
+
+// spreadLegCounts is the number of legs each strategy requires.
+var spreadLegCounts = map[SpreadStrategy]int{
+	SpreadVerticalCall:  2,
+	SpreadVerticalPut:   2,
+	SpreadCalendarCall:  2,
+	SpreadCalendarPut:   2,
+	SpreadDiagonalCall:  2,
+	SpreadDiagonalPut:   2,
+	SpreadRatioCall:     2,
+	SpreadRatioPut:      2,
+	SpreadStraddle:      2,
+	SpreadStrangle:      2,
+	SpreadCustom2Legs:   2,
+	SpreadButterflyCall: 3,
+	SpreadButterflyPut:  3,
+	SpreadCustom3Legs:   3,
+	SpreadCondorCall:    4,
+	SpreadCondorPut:     4,
+	SpreadIronCondor:    4,
+	SpreadCustom4Legs:   4,
+}
+
+// Net pricing for spread orders; LimitPrice is the net of the whole package.
+const (
+	OrderTypeNetCredit OrderType = "NetCredit"
+	OrderTypeNetDebit  OrderType = "NetDebit"
+)
+
+// spreadOrderTypeCodes maps the order types accepted for spreads to Schwab codes.
+var spreadOrderTypeCodes = map[OrderType]string{
+	OrderTypeMarket:    "49",
+	OrderTypeNetCredit: "201",
+	OrderTypeNetDebit:  "202",
+}
+
+// SpreadLeg is one contract in a spread order.
+type SpreadLeg struct {
+	Contract    OptionContractID
+	Instruction OptionInstruction
+	Quantity    float64
+}
+
+// SpreadOrderRequest describes a multi-leg option order for PlaceSpreadOrder.
+type SpreadOrderRequest struct {
+	AccountID string
+	Strategy  SpreadStrategy
+	Legs      []SpreadLeg
+	// Type is OrderTypeNetDebit or OrderTypeNetCredit with LimitPrice, or OrderTypeMarket.
+	Type       OrderType
+	LimitPrice float64
+	// Duration is DurationDay (default) or DurationGTC.
+	Duration Duration
+	DryRun   bool
+}
+
+// validate checks the request and returns the OCC symbol of each leg.
+func (o SpreadOrderRequest) validate() ([]string, error) {
+	want, ok := spreadLegCounts[o.Strategy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported spread strategy %d", o.Strategy)
+	}
+	if len(o.Legs) != want {
+		return nil, fmt.Errorf("spread strategy %d requires %d legs, got %d", o.Strategy, want, len(o.Legs))
+	}
+	switch o.Type {
+	case OrderTypeNetCredit, OrderTypeNetDebit:
+		if o.LimitPrice <= 0 {
+			return nil, fmt.Errorf("%s order requires a positive limit price", o.Type)
+		}
+	case OrderTypeMarket:
+		if o.LimitPrice != 0 {
+			return nil, fmt.Errorf("%s order does not take a limit price", o.Type)
+		}
+	default:
+		return nil, fmt.Errorf("spread orders require order type %s, %s or %s", OrderTypeNetDebit, OrderTypeNetCredit, OrderTypeMarket)
+	}
+	switch o.Duration {
+	case "", DurationDay, DurationGTC:
+	default:
+		return nil, fmt.Errorf("option orders do not support duration %q", o.Duration)
+	}
+
+	symbols := make([]string, len(o.Legs))
+	for i, leg := range o.Legs {
+		if _, ok := optionInstructionCodes[leg.Instruction]; !ok {
+			return nil, fmt.Errorf("leg %d: unsupported option instruction %q", i, leg.Instruction)
+		}
+		if leg.Quantity <= 0 {
+			return nil, fmt.Errorf("leg %d: quantity must be positive", i)
+		}
+		symbol, err := leg.Contract.Symbol()
+		if err != nil {
+			return nil, fmt.Errorf("leg %d: %w", i, err)
+		}
+		symbols[i] = symbol
+	}
+	return symbols, nil
+}
+
+// payload builds the OrderVerificationV2Url request body for the verification step.
+func (o SpreadOrderRequest) payload(symbols []string) map[string]interface{} {
+	legs := make([]map[string]interface{}, len(o.Legs))
+	for i, leg := range o.Legs {
+		legs[i] = optionLeg(symbols[i], leg.Instruction, leg.Quantity)
+	}
+	return map[string]interface{}{
+		"UserContext": map[string]interface{}{
+			"AccountId":    o.AccountID,
+			"AccountColor": 0,
+		},
+		"OrderStrategy":          optionOrderStrategy(spreadOrderTypeCodes[o.Type], o.LimitPrice, o.Duration, int(o.Strategy), legs),
+		"OrderProcessingControl": 1, // Verification
+	}
+}
+
+// PlaceSpreadOrder verifies a multi-leg option order as one package and, unless order.DryRun
+// is set, executes it. The result covers the whole package.
+func (c *Client) PlaceSpreadOrder(order SpreadOrderRequest) (TradeResult, error) {
+	return c.PlaceSpreadOrderContext(context.Background(), order)
+}
+
+// PlaceSpreadOrderContext is like PlaceSpreadOrder but uses ctx for cancellation and deadlines.
+func (c *Client) PlaceSpreadOrderContext(ctx context.Context, order SpreadOrderRequest) (TradeResult, error) {
+	symbols, err := order.validate()
+	if err != nil {
+		return TradeResult{}, err
+	}
+	return c.submitOrder(ctx, order.payload(symbols), order.DryRun)
+}
@@ -0,0 +1,101 @@
+package schwab
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func ironCondorLegs() []SpreadLeg {
+	exp := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	leg := func(strike float64, right OptionRight, in OptionInstruction) SpreadLeg {
+		return SpreadLeg{Contract: OptionContractID{Underlying: "SPY", Expiration: exp, Strike: strike, Right: right}, Instruction: in, Quantity: 1}
+	}
+	return []SpreadLeg{
+		leg(480, OptionPut, BuyToOpen),
+		leg(490, OptionPut, SellToOpen),
+		leg(510, OptionCall, SellToOpen),
+		leg(520, OptionCall, BuyToOpen),
+	}
+}
+
+func TestPlaceSpreadOrder_MapsEveryLeg(t *testing.T) {
+	var requests []map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+		// Legs come back in a different order than requested.
+		w.Write([]byte(`{"orderStrategy":{"orderId":88,"orderReturnCode":0,"orderLegs":[
+			{"schwabSecurityId":4,"instrument":{"symbol":"SPY 240315C00520000"}},
+			{"schwabSecurityId":1,"instrument":{"symbol":"SPY 240315P00480000"}},
+			{"schwabSecurityId":3,"instrument":{"symbol":"SPY 240315C00510000"}},
+			{"schwabSecurityId":2,"instrument":{"symbol":"SPY 240315P00490000"}}
+		]}}`))
+	})
+
+	result, err := c.PlaceSpreadOrder(SpreadOrderRequest{
+		AccountID:  "123",
+		Strategy:   SpreadIronCondor,
+		Legs:       ironCondorLegs(),
+		Type:       OrderTypeNetCredit,
+		LimitPrice: 1.25,
+	})
+	if err != nil || !result.Placed {
+		t.Fatalf("PlaceSpreadOrder: result=%+v err=%v", result, err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want verify + execute", len(requests))
+	}
+	verify := requests[0]["OrderStrategy"].(map[string]interface{})
+	if verify["OrderStrategyType"] != 214.0 || verify["OrderType"] != "201" || verify["LimitPrice"] != "1.25" {
+		t.Errorf("unexpected strategy: %v", verify)
+	}
+	legs := requests[1]["OrderStrategy"].(map[string]interface{})["OrderLegs"].([]interface{})
+	for i, l := range legs {
+		instrument := l.(map[string]interface{})["Instrument"].(map[string]interface{})
+		if instrument["ItemIssueId"] != float64(i+1) {
+			t.Errorf("leg %d (%v): ItemIssueId = %v, want %d", i, instrument["Symbol"], instrument["ItemIssueId"], i+1)
+		}
+	}
+}
+
+func TestApplyItemIssueIds_PositionalFallback(t *testing.T) {
+	legs := []map[string]interface{}{
+		{"Instrument": map[string]interface{}{"Symbol": "A"}},
+		{"Instrument": map[string]interface{}{"Symbol": "B"}},
+	}
+	applyItemIssueIds(legs, []OrderLeg{{SchwabSecurityId: 10}, {SchwabSecurityId: 20}})
+	if legs[0]["Instrument"].(map[string]interface{})["ItemIssueId"] != int64(10) ||
+		legs[1]["Instrument"].(map[string]interface{})["ItemIssueId"] != int64(20) {
+		t.Errorf("unexpected legs: %v", legs)
+	}
+}
+
+func TestSpreadOrderRequest_Validate(t *testing.T) {
+	legs := ironCondorLegs()
+	bad := []SpreadOrderRequest{
+		{Strategy: SpreadIronCondor, Legs: legs[:2], Type: OrderTypeNetCredit, LimitPrice: 1},
+		{Strategy: SpreadVerticalCall, Legs: legs, Type: OrderTypeNetDebit, LimitPrice: 1},
+		{Strategy: SpreadIronCondor, Legs: legs, Type: OrderTypeNetCredit},
+		{Strategy: SpreadIronCondor, Legs: legs, Type: OrderTypeLimit, LimitPrice: 1},
+		{Strategy: 999, Legs: legs, Type: OrderTypeMarket},
+	}
+	for _, o := range bad {
+		if _, err := o.validate(); err == nil {
+			t.Errorf("validate(%+v) = nil, want error", o)
+		}
+	}
+	put, call := legs[1], legs[2]
+	put.Contract.Strike, call.Contract.Strike = 500, 500
+	ok := []SpreadOrderRequest{
+		{Strategy: SpreadStraddle, Legs: []SpreadLeg{put, call}, Type: OrderTypeNetCredit, LimitPrice: 5},
+		{Strategy: SpreadStrangle, Legs: []SpreadLeg{legs[1], legs[2]}, Type: OrderTypeNetCredit, LimitPrice: 5},
+	}
+	for _, o := range ok {
+		if _, err := o.validate(); err != nil {
+			t.Errorf("validate(%+v): %v", o, err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package schwab
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TransactionAction is the normalized kind of a brokerage transaction.
+type TransactionAction string
+
+const (
+	TransactionBuy      TransactionAction = "Buy"
+	TransactionSell     TransactionAction = "Sell"
+	TransactionDividend TransactionAction = "Dividend"
+	TransactionInterest TransactionAction = "Interest"
+	TransactionJournal  TransactionAction = "Journal"
+	TransactionFee      TransactionAction = "Fee"
+	TransactionOther    TransactionAction = "Other"
+)
+
+// allTransactionTypes are the transaction categories the website export selects by default.
+var allTransactionTypes = []string{
+	"Adjustments", "AtmActivity", "BillPay", "CorporateActions", "Checks", "Deposits",
+	"DividendsAndCapitalGains", "ElectronicTransfers", "Fees", "Interest", "Misc",
+	"SecurityTransfers", "Taxes", "Trades", "VisaDebitCard", "Withdrawals",
+}
+
+// TransactionFilter narrows GetTransactionHistory. Zero values match everything.
+type TransactionFilter struct {
+	// Types are Schwab transaction categories such as "Trades" or "DividendsAndCapitalGains".
+	Types []string
+	// Symbol limits the export to one security.
+	Symbol string
+}
+
+// Transaction is one row of the brokerage transaction export.
+type Transaction struct {
+	Date        time.Time         `json:"date"` // First date of the row; a trailing "as of" date is ignored
+	Action      TransactionAction `json:"action"`
+	RawAction   string            `json:"raw_action"` // Action as exported, e.g. "Qualified Dividend"
+	Symbol      string            `json:"symbol"`
+	Description string            `json:"description"`
+	Quantity    float64           `json:"quantity"`
+	Price       float64           `json:"price"`
+	Fees        float64           `json:"fees"`
+	Amount      float64           `json:"amount"`
+}
+
+// GetTransactionHistory downloads the CSV transaction export from TransactionHistoryV2Url and parses it.
+// A zero from or to leaves that end of the date range open.
+func (c *Client) GetTransactionHistory(accountID string, from, to time.Time, filter TransactionFilter) ([]Transaction, error) {
+	return c.GetTransactionHistoryContext(context.Background(), accountID, from, to, filter)
+}
+
+// GetTransactionHistoryContext is like GetTransactionHistory but uses ctx for cancellation and deadlines.
+func (c *Client) GetTransactionHistoryContext(ctx context.Context, accountID string, from, to time.Time, filter TransactionFilter) ([]Transaction, error) {
+	if err := c.UpdateTokenContext(ctx, "api"); err != nil && c.Debug {
+		log.Printf("UpdateToken(api) warning: %v", err)
+	}
+
+	types := filter.Types
+	if len(types) == 0 {
+		types = allTransactionTypes
+	}
+	timeFrame := "All"
+	startDate, endDate := "", ""
+	if !from.IsZero() || !to.IsZero() {
+		timeFrame = "Custom"
+		if !from.IsZero() {
+			startDate = from.Format("01/02/2006")
+		}
+		if to.IsZero() {
+			to = time.Now()
+		}
+		endDate = to.Format("01/02/2006")
+	}
+	requestBody := map[string]interface{}{
+		"exportType":                      "Csv",
+		"includeOptionsInBrokerageSearch": true,
+		"selectedAccountId":               accountID,
+		"selectedSymbol":                  filter.Symbol,
+		"selectedTransactionTypes":        types,
+		"sortColumn":                      "Date",
+		"sortDirection":                   "Descending",
+		"timeFrame":                       timeFrame,
+		"startDate":                       startDate,
+		"endDate":                         endDate,
+	}
+
+	u := c.endpoint(TransactionHistoryV2Url)
+	status, body, err := c.do(ctx, "POST", u, requestBody, map[string]string{
+		"schwab-client-account": accountID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if status != 200 {
+		return nil, newAPIError("POST", u, status, body)
+	}
+	return parseTransactionsCSV(body)
+}
+
+// parseTransactionsCSV parses the export. Title lines before the header row and
+// trailer rows such as "Transactions Total" are skipped.
+func parseTransactionsCSV(data []byte) ([]Transaction, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	var columns map[string]int
+	transactions := []Transaction{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse transaction export: %w", err)
+		}
+		if columns == nil {
+			if len(record) > 0 && strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff")), "Date") {
+				columns = make(map[string]int)
+				for i, name := range record {
+					columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+				}
+			}
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		// "02/15/2024 as of 02/14/2024" -> the first date is kept and the "as of" date dropped
+		date, _, _ := strings.Cut(field("Date"), " ")
+		parsed, err := time.Parse("01/02/2006", date)
+		if err != nil {
+			continue
+		}
+		rawAction := field("Action")
+		transactions = append(transactions, Transaction{
+			Date:        parsed,
+			Action:      classifyAction(rawAction),
+			RawAction:   rawAction,
+			Symbol:      field("Symbol"),
+			Description: field("Description"),
+			Quantity:    parseAmount(field("Quantity")),
+			Price:       parseAmount(field("Price")),
+			Fees:        parseAmount(field("Fees & Comm")),
+			Amount:      parseAmount(field("Amount")),
+		})
+	}
+	if columns == nil {
+		return nil, fmt.Errorf("parse transaction export: header row not found")
+	}
+	return transactions, nil
+}
+
+// classifyAction maps an exported action such as "Reinvest Dividend" or "Sell to Close" to a TransactionAction.
+func classifyAction(action string) TransactionAction {
+	a := strings.ToLower(action)
+	switch {
+	case strings.HasPrefix(a, "buy") || a == "reinvest shares":
+		return TransactionBuy
+	case strings.HasPrefix(a, "sell"):
+		return TransactionSell
+	case strings.Contains(a, "dividend") || strings.Contains(a, "div ") || strings.Contains(a, "cap gain"):
+		return TransactionDividend
+	case strings.Contains(a, "interest"):
+		return TransactionInterest
+	case strings.Contains(a, "journal"):
+		return TransactionJournal
+	case strings.Contains(a, "fee"):
+		return TransactionFee
+	}
+	return TransactionOther
+}
+
+// parseAmount parses export values like "$1,234.56", "-$12.00" or "($12.00)"; blanks become 0.
+func parseAmount(s string) float64 {
+	negative := strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")")
+	s = strings.NewReplacer("$", "", ",", "", "(", "", ")", "").Replace(s)
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	if negative {
+		return -v
+	}
+	return v
+}
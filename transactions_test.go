@@ -0,0 +1,84 @@
+package schwab
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const sampleTransactionsCSV = `"Transactions  for account XXXX-1234 as of 03/01/2024 10:00:00 ET"
+"Date","Action","Symbol","Description","Quantity","Price","Fees & Comm","Amount"
+"02/28/2024","Buy","AAPL","APPLE INC","10","$180.50","$0.00","-$1,805.00"
+"02/27/2024 as of 02/26/2024","Sell","MSFT","MICROSOFT CORP","5","$400.00","$0.05","$1,999.95"
+"02/15/2024","Qualified Dividend","VTI","VANGUARD TOTAL STOCK MKT ETF","","","","$25.10"
+"02/01/2024","Bank Interest","","BANK INT 010224-013124","","","","$1.23"
+"01/31/2024","Journaled Shares","SPY","SPDR S&P 500","2","","",""
+"01/15/2024","ADR Mgmt Fee","TSM","TAIWAN SEMICONDUCTOR","","","","($0.40)"
+"Transactions Total","","","","","","","$220.88"
+`
+
+func TestParseTransactionsCSV(t *testing.T) {
+	txs, err := parseTransactionsCSV([]byte(sampleTransactionsCSV))
+	if err != nil {
+		t.Fatalf("parseTransactionsCSV: %v", err)
+	}
+	want := []TransactionAction{TransactionBuy, TransactionSell, TransactionDividend, TransactionInterest, TransactionJournal, TransactionFee}
+	if len(txs) != len(want) {
+		t.Fatalf("got %d transactions, want %d", len(txs), len(want))
+	}
+	for i, a := range want {
+		if txs[i].Action != a {
+			t.Errorf("row %d: Action = %s, want %s (raw %q)", i, txs[i].Action, a, txs[i].RawAction)
+		}
+	}
+	buy := txs[0]
+	if buy.Symbol != "AAPL" || buy.Quantity != 10 || buy.Price != 180.5 || buy.Amount != -1805 {
+		t.Errorf("unexpected buy: %+v", buy)
+	}
+	if !txs[1].Date.Equal(time.Date(2024, 2, 27, 0, 0, 0, 0, time.UTC)) || txs[1].Fees != 0.05 {
+		t.Errorf("unexpected sell: %+v", txs[1])
+	}
+	if txs[5].Amount != -0.4 {
+		t.Errorf("fee Amount = %v, want -0.4", txs[5].Amount)
+	}
+}
+
+func TestParseTransactionsCSV_AsOfDate(t *testing.T) {
+	csv := `"Date","Action","Symbol","Description","Quantity","Price","Fees & Comm","Amount"
+"03/04/2024 as of 03/01/2024","Qualified Dividend","VTI","VANGUARD TOTAL STOCK MARKET ETF","","","","$12.34"
+`
+	txs, err := parseTransactionsCSV([]byte(csv))
+	if err != nil {
+		t.Fatalf("parseTransactionsCSV: %v", err)
+	}
+	if len(txs) != 1 || !txs[0].Date.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) || txs[0].Amount != 12.34 {
+		t.Errorf("unexpected transactions: %+v", txs)
+	}
+}
+
+func TestParseTransactionsCSV_NoHeader(t *testing.T) {
+	if _, err := parseTransactionsCSV([]byte("<html>maintenance</html>")); err == nil {
+		t.Fatal("expected error without header row")
+	}
+}
+
+func TestGetTransactionHistory(t *testing.T) {
+	var body map[string]interface{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(sampleTransactionsCSV))
+	})
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	txs, err := c.GetTransactionHistory("123", from, to, TransactionFilter{Types: []string{"Trades"}})
+	if err != nil {
+		t.Fatalf("GetTransactionHistory: %v", err)
+	}
+	if len(txs) != 6 {
+		t.Errorf("got %d transactions, want 6", len(txs))
+	}
+	if body["timeFrame"] != "Custom" || body["startDate"] != "01/01/2024" || body["endDate"] != "03/01/2024" || body["selectedAccountId"] != "123" {
+		t.Errorf("unexpected request body: %v", body)
+	}
+}